@@ -0,0 +1,167 @@
+package main
+
+import (
+	"crypto/subtle"
+	"net/http"
+	"sort"
+	"strings"
+	"time"
+)
+
+// adminSecret is the shared secret required by the /api/admin/ endpoints.
+// When it is empty the admin API is disabled.
+var adminSecret string
+
+// requireAdmin rejects requests that don't carry the admin secret as a
+// bearer token
+func requireAdmin(next http.HandlerFunc) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		if adminSecret == "" {
+			writeError(w, ErrEndpointNotFound)
+			return
+		}
+
+		token, ok := strings.CutPrefix(r.Header.Get("Authorization"), "Bearer ")
+		if !ok || subtle.ConstantTimeCompare([]byte(token), []byte(adminSecret)) != 1 {
+			w.Header().Set("WWW-Authenticate", `Bearer realm="admin"`)
+			writeError(w, ErrUnauthorized)
+			return
+		}
+
+		next(w, r)
+	}
+}
+
+// Admin views of rooms
+
+func summarizeRoom(room *Room, now time.Time) AdminRoomSummary {
+	players := make([]string, len(room.Players))
+	copy(players, room.Players)
+	return AdminRoomSummary{
+		RoomCode:    room.RoomCode,
+		Players:     players,
+		GridSize:    room.GridSize,
+		GameStarted: room.GameStarted,
+		GameOver:    room.GameOver,
+		Public:      room.Public,
+		Private:     room.IsPrivate(),
+		CreatedAt:   room.CreatedAt,
+		AgeSeconds:  int64(now.Sub(room.CreatedAt).Seconds()),
+	}
+}
+
+// ListRoomSummaries returns a summary of every room, sorted by room code
+func ListRoomSummaries() []AdminRoomSummary {
+	roomsMu.RLock()
+	all := make([]*Room, 0, len(rooms))
+	for _, room := range rooms {
+		all = append(all, room)
+	}
+	roomsMu.RUnlock()
+
+	now := time.Now()
+	summaries := make([]AdminRoomSummary, 0, len(all))
+	for _, room := range all {
+		room.mu.RLock()
+		summaries = append(summaries, summarizeRoom(room, now))
+		room.mu.RUnlock()
+	}
+
+	sort.Slice(summaries, func(i, j int) bool {
+		return summaries[i].RoomCode < summaries[j].RoomCode
+	})
+	return summaries
+}
+
+// GetRoomDetail returns the full state of a room, including hidden fields
+func GetRoomDetail(roomCode string) (*AdminRoomDetail, error) {
+	room, exists := getRoom(roomCode)
+	if !exists {
+		return nil, ErrRoomNotFound
+	}
+
+	room.mu.RLock()
+	defer room.mu.RUnlock()
+
+	grid := make([][]AdminCell, room.GridSize)
+	for row := 0; row < room.GridSize; row++ {
+		grid[row] = make([]AdminCell, room.GridSize)
+		for col := 0; col < room.GridSize; col++ {
+			cell := room.Grid[row][col]
+			grid[row][col] = AdminCell{
+				GuessedCorrectly: cell.GuessedCorrectly,
+				DiscardedBy:      cell.DiscardedBy,
+			}
+		}
+	}
+
+	deck := make([]Card, len(room.CardDeck))
+	copy(deck, room.CardDeck)
+
+	hands := make(map[string][]Card, len(room.PlayerHands))
+	for player, cards := range room.PlayerHands {
+		hand := make([]Card, len(cards))
+		copy(hand, cards)
+		hands[player] = hand
+	}
+
+	return &AdminRoomDetail{
+		AdminRoomSummary: summarizeRoom(room, time.Now()),
+		RowWords:         append([]string(nil), room.RowWords...),
+		ColumnWords:      append([]string(nil), room.ColumnWords...),
+		Grid:             grid,
+		CardDeck:         deck,
+		PlayerHands:      hands,
+	}, nil
+}
+
+// Admin HTTP handlers
+
+func handleAdminListRooms(w http.ResponseWriter, r *http.Request) {
+	writeJSON(w, http.StatusOK, AdminRoomListResponse{Rooms: ListRoomSummaries()})
+}
+
+func handleAdminGetRoom(w http.ResponseWriter, r *http.Request) {
+	detail, err := GetRoomDetail(r.PathValue("code"))
+	if err != nil {
+		writeError(w, err)
+		return
+	}
+	writeJSON(w, http.StatusOK, detail)
+}
+
+func handleAdminDeleteRoom(w http.ResponseWriter, r *http.Request) {
+	roomCode := normalizeRoomCode(r.PathValue("code"))
+	if err := DeleteRoom(roomCode); err != nil {
+		writeError(w, err)
+		return
+	}
+	writeJSON(w, http.StatusOK, RoomMessageResponse{
+		RoomCode: roomCode,
+		Message:  "Room deleted",
+	})
+}
+
+func handleAdminEndGame(w http.ResponseWriter, r *http.Request) {
+	roomCode := normalizeRoomCode(r.PathValue("code"))
+	if err := EndGame(roomCode); err != nil {
+		writeError(w, err)
+		return
+	}
+	writeJSON(w, http.StatusOK, RoomMessageResponse{
+		RoomCode: roomCode,
+		Message:  "Game ended",
+	})
+}
+
+func handleAdminRemovePlayer(w http.ResponseWriter, r *http.Request) {
+	roomCode := normalizeRoomCode(r.PathValue("code"))
+	if err := LeaveRoom(roomCode, r.PathValue("name")); err != nil {
+		writeError(w, err)
+		return
+	}
+	writeJSON(w, http.StatusOK, RoomMessageResponse{
+		RoomCode: roomCode,
+		Message:  "Player removed",
+	})
+}
@@ -2,16 +2,24 @@ package main
 
 import (
 	"encoding/json"
+	"errors"
 	"net/http"
 	"strings"
+	"time"
 )
 
 // CORS middleware to allow cross-origin requests from frontend
 func enableCORS(next http.HandlerFunc) http.HandlerFunc {
 	return func(w http.ResponseWriter, r *http.Request) {
-		w.Header().Set("Access-Control-Allow-Origin", "*")
-		w.Header().Set("Access-Control-Allow-Methods", "GET, POST, OPTIONS")
-		w.Header().Set("Access-Control-Allow-Headers", "Content-Type")
+		if origin := allowedOrigin(r.Header.Get("Origin")); origin != "" {
+			w.Header().Set("Access-Control-Allow-Origin", origin)
+			if origin != "*" {
+				w.Header().Add("Vary", "Origin")
+			}
+		}
+		w.Header().Set("Access-Control-Allow-Methods", "GET, POST, DELETE, OPTIONS")
+		w.Header().Set("Access-Control-Allow-Headers", "Content-Type, X-Request-ID")
+		w.Header().Set("Access-Control-Expose-Headers", "X-Request-ID")
 
 		// Handle preflight requests
 		if r.Method == http.MethodOptions {
@@ -23,6 +31,20 @@ func enableCORS(next http.HandlerFunc) http.HandlerFunc {
 	}
 }
 
+// allowedOrigin returns the Access-Control-Allow-Origin value for a request
+// origin, or "" if the origin is not allowed
+func allowedOrigin(origin string) string {
+	for _, allowed := range config.CORSOrigins {
+		if allowed == "*" {
+			return "*"
+		}
+		if origin != "" && strings.EqualFold(allowed, origin) {
+			return origin
+		}
+	}
+	return ""
+}
+
 // Helper functions for HTTP responses
 
 func writeJSON(w http.ResponseWriter, status int, data interface{}) {
@@ -31,167 +53,128 @@ func writeJSON(w http.ResponseWriter, status int, data interface{}) {
 	json.NewEncoder(w).Encode(data)
 }
 
-func writeError(w http.ResponseWriter, status int, message string) {
-	writeJSON(w, status, ErrorResponse{Error: message})
+// writeError responds with the status and code for err. Errors that aren't
+// domain errors are reported as internal errors.
+func writeError(w http.ResponseWriter, err error) {
+	e, status := httpError(err)
+	writeJSON(w, status, ErrorResponse{
+		Error:     e.Message,
+		Code:      e.Code,
+		Details:   e.Details,
+		RequestID: w.Header().Get(requestIDHeader),
+	})
 }
 
 // HTTP Handlers
 
 func handleCreateRoom(w http.ResponseWriter, r *http.Request) {
-	if r.Method != http.MethodPost {
-		writeError(w, http.StatusMethodNotAllowed, "Method not allowed")
+	if isShuttingDown() {
+		writeError(w, ErrShuttingDown)
 		return
 	}
 
 	var req CreateRoomRequest
 	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
-		writeError(w, http.StatusBadRequest, "Invalid request body")
-		return
-	}
-
-	if strings.TrimSpace(req.RoomCode) == "" {
-		writeError(w, http.StatusBadRequest, "Room code is required")
+		writeError(w, ErrInvalidBody)
 		return
 	}
 
+	logPlayer(r, req.PlayerName)
 	if strings.TrimSpace(req.PlayerName) == "" {
-		writeError(w, http.StatusBadRequest, "Player name is required")
+		writeError(w, ErrPlayerNameRequired)
 		return
 	}
 
-	// Default grid size if not specified
-	gridSize := req.GridSize
-	if gridSize == 0 {
-		gridSize = DefaultGridSize
-	}
-	if gridSize < MinGridSize || gridSize > MaxGridSize {
-		writeError(w, http.StatusBadRequest, "Grid size must be between 3 and 7")
-		return
-	}
-
-	room, err := CreateRoom(req.RoomCode, gridSize, req.PlayerName)
+	room, playerName, err := CreateRoomWithOptions(req.RoomCode, req.PlayerName, RoomOptions{
+		GridSize:   req.GridSize,
+		WordPack:   req.WordPack,
+		Password:   req.Password,
+		Public:     req.Public,
+		MaxPlayers: req.MaxPlayers,
+	})
 	if err != nil {
-		writeError(w, http.StatusConflict, err.Error())
+		writeError(w, err)
 		return
 	}
 
+	logRoom(r, room.RoomCode)
 	writeJSON(w, http.StatusCreated, CreateRoomResponse{
 		RoomCode:   room.RoomCode,
-		PlayerName: req.PlayerName,
+		PlayerName: playerName,
 		Message:    "Room created successfully",
 	})
 }
 
 func handleJoinRoom(w http.ResponseWriter, r *http.Request) {
-	if r.Method != http.MethodPost {
-		writeError(w, http.StatusMethodNotAllowed, "Method not allowed")
-		return
-	}
-
-	// Extract room code from URL: /api/rooms/{roomCode}/join
-	path := strings.TrimPrefix(r.URL.Path, "/api/rooms/")
-	parts := strings.Split(path, "/")
-	if len(parts) != 2 || parts[1] != "join" {
-		writeError(w, http.StatusBadRequest, "Invalid URL")
-		return
-	}
-	roomCode := parts[0]
+	roomCode := normalizeRoomCode(r.PathValue("code"))
 
 	var req JoinRoomRequest
 	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
-		writeError(w, http.StatusBadRequest, "Invalid request body")
+		writeError(w, ErrInvalidBody)
 		return
 	}
 
+	logPlayer(r, req.PlayerName)
 	if strings.TrimSpace(req.PlayerName) == "" {
-		writeError(w, http.StatusBadRequest, "Player name is required")
+		writeError(w, ErrPlayerNameRequired)
+		return
+	}
+
+	// Wrong password attempts are limited per client and room
+	attemptKey := roomCode + "|" + clientIP(r)
+	if blocked, wait := passwordLimiter.Blocked(attemptKey, time.Now()); blocked {
+		writeRateLimited(w, wait)
 		return
 	}
 
-	cardsDealt, err := JoinRoom(roomCode, req.PlayerName)
+	cardsDealt, err := JoinRoomWithPassword(roomCode, req.PlayerName, req.Password)
 	if err != nil {
-		if err.Error() == "room not found" {
-			writeError(w, http.StatusNotFound, "Room not found")
-		} else {
-			writeError(w, http.StatusConflict, err.Error())
+		if errors.Is(err, ErrWrongPassword) {
+			passwordLimiter.Allow(attemptKey, time.Now())
 		}
+		writeError(w, err)
 		return
 	}
 
 	writeJSON(w, http.StatusOK, JoinRoomResponse{
 		RoomCode:   roomCode,
-		PlayerName: req.PlayerName,
+		PlayerName: normalizePlayerName(req.PlayerName),
 		CardsDealt: cardsDealt,
 		Message:    "Joined room successfully",
 	})
 }
 
 func handleLeaveRoom(w http.ResponseWriter, r *http.Request) {
-	if r.Method != http.MethodPost {
-		writeError(w, http.StatusMethodNotAllowed, "Method not allowed")
-		return
-	}
-
-	// Extract room code from URL: /api/rooms/{roomCode}/leave
-	path := strings.TrimPrefix(r.URL.Path, "/api/rooms/")
-	parts := strings.Split(path, "/")
-	if len(parts) != 2 || parts[1] != "leave" {
-		writeError(w, http.StatusBadRequest, "Invalid URL")
-		return
-	}
-	roomCode := parts[0]
+	roomCode := normalizeRoomCode(r.PathValue("code"))
 
 	var req JoinRoomRequest // Reuse JoinRoomRequest since it only needs playerName
 	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
-		writeError(w, http.StatusBadRequest, "Invalid request body")
+		writeError(w, ErrInvalidBody)
 		return
 	}
 
+	logPlayer(r, req.PlayerName)
 	if strings.TrimSpace(req.PlayerName) == "" {
-		writeError(w, http.StatusBadRequest, "Player name is required")
+		writeError(w, ErrPlayerNameRequired)
 		return
 	}
 
-	err := LeaveRoom(roomCode, req.PlayerName)
-	if err != nil {
-		if err == ErrRoomNotFound {
-			writeError(w, http.StatusNotFound, "Room not found")
-		} else if err == ErrPlayerNotFound {
-			writeError(w, http.StatusNotFound, "Player not found in room")
-		} else {
-			writeError(w, http.StatusBadRequest, err.Error())
-		}
+	if err := LeaveRoom(roomCode, req.PlayerName); err != nil {
+		writeError(w, err)
 		return
 	}
 
-	writeJSON(w, http.StatusOK, map[string]string{
-		"roomCode": roomCode,
-		"message":  "Left room successfully",
+	writeJSON(w, http.StatusOK, RoomMessageResponse{
+		RoomCode: roomCode,
+		Message:  "Left room successfully",
 	})
 }
 
 func handleStartGame(w http.ResponseWriter, r *http.Request) {
-	if r.Method != http.MethodPost {
-		writeError(w, http.StatusMethodNotAllowed, "Method not allowed")
-		return
-	}
-
-	// Extract room code from URL: /api/rooms/{roomCode}/start
-	path := strings.TrimPrefix(r.URL.Path, "/api/rooms/")
-	parts := strings.Split(path, "/")
-	if len(parts) != 2 || parts[1] != "start" {
-		writeError(w, http.StatusBadRequest, "Invalid URL")
-		return
-	}
-	roomCode := parts[0]
+	roomCode := normalizeRoomCode(r.PathValue("code"))
 
-	err := StartGame(roomCode)
-	if err != nil {
-		if err.Error() == "room not found" {
-			writeError(w, http.StatusNotFound, "Room not found")
-		} else {
-			writeError(w, http.StatusBadRequest, err.Error())
-		}
+	if err := StartGame(roomCode); err != nil {
+		writeError(w, err)
 		return
 	}
 
@@ -202,40 +185,24 @@ func handleStartGame(w http.ResponseWriter, r *http.Request) {
 }
 
 func handleGuess(w http.ResponseWriter, r *http.Request) {
-	if r.Method != http.MethodPost {
-		writeError(w, http.StatusMethodNotAllowed, "Method not allowed")
-		return
-	}
-
-	// Extract room code from URL: /api/rooms/{roomCode}/guess
-	path := strings.TrimPrefix(r.URL.Path, "/api/rooms/")
-	parts := strings.Split(path, "/")
-	if len(parts) != 2 || parts[1] != "guess" {
-		writeError(w, http.StatusBadRequest, "Invalid URL")
-		return
-	}
-	roomCode := parts[0]
+	roomCode := normalizeRoomCode(r.PathValue("code"))
 
 	var req GuessRequest
 	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
-		writeError(w, http.StatusBadRequest, "Invalid request body")
+		writeError(w, ErrInvalidBody)
 		return
 	}
+	logPlayer(r, req.PlayerName)
 
 	// Basic validation - detailed validation happens in SubmitGuess
-	if req.Row < 0 || req.Row >= MaxGridSize || req.Column < 0 || req.Column >= MaxGridSize {
-		writeError(w, http.StatusBadRequest, "Invalid row or column")
+	if req.Row < 0 || req.Row >= config.MaxGridSize || req.Column < 0 || req.Column >= config.MaxGridSize {
+		writeError(w, ErrInvalidCell)
 		return
 	}
 
 	gameOver, err := SubmitGuess(roomCode, req.PlayerName, req.Row, req.Column, req.Correct)
 	if err != nil {
-		switch err.Error() {
-		case "room not found", "player not found in this room":
-			writeError(w, http.StatusNotFound, err.Error())
-		default:
-			writeError(w, http.StatusBadRequest, err.Error())
-		}
+		writeError(w, err)
 		return
 	}
 
@@ -247,67 +214,24 @@ func handleGuess(w http.ResponseWriter, r *http.Request) {
 }
 
 func handleGetState(w http.ResponseWriter, r *http.Request) {
-	if r.Method != http.MethodGet {
-		writeError(w, http.StatusMethodNotAllowed, "Method not allowed")
-		return
-	}
-
-	// Extract room code from URL: /api/rooms/{roomCode}/state
-	path := strings.TrimPrefix(r.URL.Path, "/api/rooms/")
-	parts := strings.Split(path, "/")
-	if len(parts) != 2 || parts[1] != "state" {
-		writeError(w, http.StatusBadRequest, "Invalid URL")
-		return
-	}
-	roomCode := parts[0]
+	roomCode := normalizeRoomCode(r.PathValue("code"))
 
 	playerName := r.URL.Query().Get("playerName")
 	if playerName == "" {
-		writeError(w, http.StatusBadRequest, "playerName query parameter is required")
+		writeError(w, ErrPlayerNameRequired)
 		return
 	}
 
 	state, err := GetGameState(roomCode, playerName)
 	if err != nil {
-		writeError(w, http.StatusNotFound, err.Error())
-		return
-	}
-
-	writeJSON(w, http.StatusOK, state)
-}
-
-// Router
-func handleRooms(w http.ResponseWriter, r *http.Request) {
-	path := strings.TrimPrefix(r.URL.Path, "/api/rooms")
-
-	// POST /api/rooms - Create room
-	if path == "" || path == "/" {
-		handleCreateRoom(w, r)
+		writeError(w, err)
 		return
 	}
-
-	// Remove leading slash
-	path = strings.TrimPrefix(path, "/")
-	parts := strings.Split(path, "/")
-
-	if len(parts) == 2 {
-		action := parts[1]
-		switch action {
-		case "join":
-			handleJoinRoom(w, r)
-		case "leave":
-			handleLeaveRoom(w, r)
-		case "start":
-			handleStartGame(w, r)
-		case "guess":
-			handleGuess(w, r)
-		case "state":
-			handleGetState(w, r)
-		default:
-			writeError(w, http.StatusNotFound, "Endpoint not found")
+	if state.GameOver {
+		if share, err := lastShare(roomCode, publicBaseURL(r)); err == nil {
+			state.Share = share.Text
 		}
-		return
 	}
 
-	writeError(w, http.StatusNotFound, "Endpoint not found")
+	writeJSON(w, http.StatusOK, state)
 }
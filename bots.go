@@ -0,0 +1,456 @@
+package main
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"math"
+	"math/rand"
+	"net/http"
+	"strings"
+	"sync"
+	"time"
+)
+
+// Bots are server-side players. They join rooms like humans, are dealt
+// cards with DrawCard, and resolve their cards on their own goroutine as
+// decided by a Strategy.
+
+const (
+	defaultBotThinkTime = 3 * time.Second
+	maxBotThinkTime     = time.Minute
+
+	// maxBotsPerRoom caps a room's bots even when the room itself has no
+	// player limit, since each bot runs its own goroutine
+	maxBotsPerRoom = 8
+
+	// botIdlePoll bounds how long an idle bot waits before checking that its
+	// room still exists
+	botIdlePoll = 5 * time.Second
+)
+
+// BotView is what a strategy sees: the game from the bot's seat
+type BotView struct {
+	Name  string
+	State *GameStateResponse
+}
+
+// Strategy decides how a bot resolves its cards. Decide is called after each
+// think delay while the bot's game is running and it holds cards; ok=false
+// passes this turn.
+type Strategy interface {
+	Name() string
+	Decide(view BotView) (card Card, correct bool, ok bool)
+}
+
+// RandomStrategy resolves a random card, marking it guessed with probability
+// CorrectRate and discarding it otherwise
+type RandomStrategy struct {
+	CorrectRate float64
+}
+
+func (RandomStrategy) Name() string { return "random" }
+
+func (s RandomStrategy) Decide(view BotView) (Card, bool, bool) {
+	cards := view.State.PlayerCards
+	if len(cards) == 0 {
+		return Card{}, false, false
+	}
+	return cards[rand.Intn(len(cards))], rand.Float64() < s.CorrectRate, true
+}
+
+// DiscardStrategy discards every card it is dealt
+type DiscardStrategy struct{}
+
+func (DiscardStrategy) Name() string { return "discard" }
+
+func (DiscardStrategy) Decide(view BotView) (Card, bool, bool) {
+	if len(view.State.PlayerCards) == 0 {
+		return Card{}, false, false
+	}
+	return view.State.PlayerCards[0], false, true
+}
+
+// ScriptedStrategy resolves the first card in hand with each outcome of its
+// script in turn (true for guessed, false for discarded), then passes
+type ScriptedStrategy struct {
+	mu     sync.Mutex
+	script []bool
+	next   int
+}
+
+func NewScriptedStrategy(script ...bool) *ScriptedStrategy {
+	return &ScriptedStrategy{script: script}
+}
+
+func (*ScriptedStrategy) Name() string { return "scripted" }
+
+func (s *ScriptedStrategy) Decide(view BotView) (Card, bool, bool) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.next >= len(s.script) || len(view.State.PlayerCards) == 0 {
+		return Card{}, false, false
+	}
+	correct := s.script[s.next]
+	s.next++
+	return view.State.PlayerCards[0], correct, true
+}
+
+// ClueStrategy plays both sides of the table from the association list. It
+// clues the card it can clue best, then lets the guesser pick a cell for
+// that clue: the card is guessed if the guesser lands on it and discarded
+// otherwise. Cells discarded by other players look open to it, as they do
+// to a human.
+type ClueStrategy struct{}
+
+func (ClueStrategy) Name() string { return "clue" }
+
+func (ClueStrategy) Decide(view BotView) (Card, bool, bool) {
+	state := view.State
+	if len(state.PlayerCards) == 0 {
+		return Card{}, false, false
+	}
+
+	board := clueBoard{RowWords: state.RowWords, ColumnWords: state.ColumnWords}
+	for row, cells := range state.Grid {
+		for col, cell := range cells {
+			if !cell.GuessedCorrectly && !cell.DiscardedByMe {
+				board.Open = append(board.Open, Card{Row: row, Column: col})
+			}
+		}
+	}
+
+	best, bestScore, clue := state.PlayerCards[0], math.Inf(-1), ""
+	for _, card := range state.PlayerCards {
+		if ranked := associations.rankClues(board, card); len(ranked) > 0 && ranked[0].score() > bestScore {
+			best, bestScore, clue = card, ranked[0].score(), ranked[0].clue
+		}
+	}
+	if clue == "" {
+		return best, false, true
+	}
+	return best, associations.guess(board, clue) == best, true
+}
+
+// newStrategy builds a strategy by name for the HTTP API
+func newStrategy(name string, script []bool) (Strategy, error) {
+	switch name {
+	case "", "random":
+		return RandomStrategy{CorrectRate: 0.75}, nil
+	case "discard":
+		return DiscardStrategy{}, nil
+	case "scripted":
+		return NewScriptedStrategy(script...), nil
+	case "clue":
+		return ClueStrategy{}, nil
+	}
+	return nil, ErrUnknownStrategy
+}
+
+// BotOptions configures a bot. Each think delay is drawn uniformly from
+// half to one and a half times ThinkTime.
+type BotOptions struct {
+	ThinkTime time.Duration
+}
+
+type bot struct {
+	room     string
+	name     string
+	strategy Strategy
+	think    time.Duration
+
+	stop     chan struct{}
+	stopOnce sync.Once
+	done     chan struct{} // Closed when run returns
+}
+
+// Running bots
+var (
+	botsMu sync.Mutex
+	bots   = make(map[string]map[string]*bot) // room code -> bot name -> bot
+)
+
+// AddBot seats a bot named "Bot N" in a room and starts it. playerName must
+// be seated in the room, and password is needed if the room is private.
+func AddBot(roomCode, playerName, password string, strategy Strategy, opts BotOptions) (string, error) {
+	roomCode = normalizeRoomCode(roomCode)
+	if opts.ThinkTime <= 0 {
+		opts.ThinkTime = defaultBotThinkTime
+	}
+
+	room, exists := getRoom(roomCode)
+	if !exists {
+		return "", ErrRoomNotFound
+	}
+	if err := checkSeated(room, playerName, password); err != nil {
+		return "", err
+	}
+
+	// The cap is checked and the seat taken under botsMu so concurrent adds
+	// can't overshoot it together
+	botsMu.Lock()
+	defer botsMu.Unlock()
+	if len(bots[roomCode]) >= maxBotsPerRoom {
+		return "", ErrTooManyBots
+	}
+
+	// Bot names are reserved, so they skip the checks made on human names
+	var name string
+	for n := 1; ; n++ {
+		name = fmt.Sprintf("%s%d", botNamePrefix, n)
+		_, err := room.join(name)
+		if err == nil {
+			break
+		}
+		if !errors.Is(err, ErrPlayerExists) {
+			return "", err
+		}
+	}
+
+	b := &bot{
+		room:     roomCode,
+		name:     name,
+		strategy: strategy,
+		think:    opts.ThinkTime,
+		stop:     make(chan struct{}),
+		done:     make(chan struct{}),
+	}
+	if bots[roomCode] == nil {
+		bots[roomCode] = make(map[string]*bot)
+	}
+	bots[roomCode][name] = b
+
+	go b.run()
+	return name, nil
+}
+
+// RemoveBot stops a bot and removes it from its room. As for AddBot,
+// playerName must be seated in the room.
+func RemoveBot(roomCode, playerName, password, name string) error {
+	roomCode = normalizeRoomCode(roomCode)
+
+	room, exists := getRoom(roomCode)
+	if !exists {
+		return ErrRoomNotFound
+	}
+	if err := checkSeated(room, playerName, password); err != nil {
+		return err
+	}
+
+	botsMu.Lock()
+	var b *bot
+	for botName, candidate := range bots[roomCode] {
+		if playerNameKey(botName) == playerNameKey(name) {
+			b = candidate
+			break
+		}
+	}
+	botsMu.Unlock()
+	if b == nil {
+		return ErrBotNotFound
+	}
+
+	b.halt()
+	b.unregister()
+	err := LeaveRoom(roomCode, b.name)
+	if errors.Is(err, ErrPlayerNotFound) {
+		return nil
+	}
+	return err
+}
+
+// RoomBots returns the names of the bots in a room
+func RoomBots(roomCode string) []string {
+	botsMu.Lock()
+	defer botsMu.Unlock()
+	var names []string
+	for name := range bots[normalizeRoomCode(roomCode)] {
+		names = append(names, name)
+	}
+	return names
+}
+
+// stopAllBots stops every bot without removing it from its room, and waits
+// for any move in progress to finish
+func stopAllBots() {
+	botsMu.Lock()
+	all := bots
+	bots = make(map[string]map[string]*bot)
+	botsMu.Unlock()
+
+	for _, room := range all {
+		for _, b := range room {
+			b.halt()
+		}
+	}
+	for _, room := range all {
+		for _, b := range room {
+			<-b.done
+		}
+	}
+}
+
+func (b *bot) halt() {
+	b.stopOnce.Do(func() { close(b.stop) })
+}
+
+func (b *bot) unregister() {
+	botsMu.Lock()
+	defer botsMu.Unlock()
+	if bots[b.room][b.name] == b {
+		delete(bots[b.room], b.name)
+		if len(bots[b.room]) == 0 {
+			delete(bots, b.room)
+		}
+	}
+}
+
+// thinkDelay returns a random delay around the bot's think time
+func (b *bot) thinkDelay() time.Duration {
+	return b.think/2 + time.Duration(rand.Int63n(int64(b.think)+1))
+}
+
+// run plays until the bot is stopped, leaves the room or the room goes away
+func (b *bot) run() {
+	defer close(b.done)
+	defer b.unregister()
+
+	changes, stopWatching := WatchRoom(b.room)
+	defer stopWatching()
+
+	for {
+		state, err := GetGameState(b.room, b.name)
+		if err != nil {
+			// The room was deleted or the bot was removed from it
+			return
+		}
+
+		if !state.GameStarted || state.GameOver || len(state.PlayerCards) == 0 {
+			select {
+			case <-b.stop:
+				return
+			case <-shutdownStarted():
+				return
+			case <-changes:
+			case <-time.After(botIdlePoll):
+			}
+			continue
+		}
+
+		timer := time.NewTimer(b.thinkDelay())
+		select {
+		case <-b.stop:
+			timer.Stop()
+			return
+		case <-shutdownStarted():
+			timer.Stop()
+			return
+		case <-timer.C:
+		}
+
+		// The game may have moved on while the bot was thinking
+		state, err = GetGameState(b.room, b.name)
+		if err != nil {
+			return
+		}
+		if !state.GameStarted || state.GameOver {
+			continue
+		}
+		card, correct, ok := b.strategy.Decide(BotView{Name: b.name, State: state})
+		if !ok {
+			continue
+		}
+		// Errors here mean the game changed under us; the next state read
+		// picks that up
+		SubmitGuess(b.room, b.name, card.Row, card.Column, correct)
+	}
+}
+
+// HTTP Handlers
+
+func handleAddBot(w http.ResponseWriter, r *http.Request) {
+	if isShuttingDown() {
+		writeError(w, ErrShuttingDown)
+		return
+	}
+	roomCode := normalizeRoomCode(r.PathValue("code"))
+
+	var req AddBotRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		writeError(w, ErrInvalidBody)
+		return
+	}
+
+	logPlayer(r, req.PlayerName)
+	if strings.TrimSpace(req.PlayerName) == "" {
+		writeError(w, ErrPlayerNameRequired)
+		return
+	}
+
+	strategy, err := newStrategy(req.Strategy, req.Script)
+	if err != nil {
+		writeError(w, err)
+		return
+	}
+	think := time.Duration(req.ThinkMs) * time.Millisecond
+	if think > maxBotThinkTime {
+		think = maxBotThinkTime
+	}
+
+	// Wrong passwords count against the same limit as joins
+	attemptKey := roomCode + "|" + clientIP(r)
+	if blocked, wait := passwordLimiter.Blocked(attemptKey, time.Now()); blocked {
+		writeRateLimited(w, wait)
+		return
+	}
+
+	name, err := AddBot(roomCode, req.PlayerName, req.Password, strategy, BotOptions{ThinkTime: think})
+	if err != nil {
+		if errors.Is(err, ErrWrongPassword) {
+			passwordLimiter.Allow(attemptKey, time.Now())
+		}
+		writeError(w, err)
+		return
+	}
+
+	writeJSON(w, http.StatusCreated, AddBotResponse{
+		RoomCode: roomCode,
+		BotName:  name,
+		Strategy: strategy.Name(),
+	})
+}
+
+func handleRemoveBot(w http.ResponseWriter, r *http.Request) {
+	roomCode := normalizeRoomCode(r.PathValue("code"))
+
+	var req RemoveBotRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		writeError(w, ErrInvalidBody)
+		return
+	}
+
+	logPlayer(r, req.PlayerName)
+	if strings.TrimSpace(req.PlayerName) == "" {
+		writeError(w, ErrPlayerNameRequired)
+		return
+	}
+
+	attemptKey := roomCode + "|" + clientIP(r)
+	if blocked, wait := passwordLimiter.Blocked(attemptKey, time.Now()); blocked {
+		writeRateLimited(w, wait)
+		return
+	}
+
+	if err := RemoveBot(roomCode, req.PlayerName, req.Password, r.PathValue("name")); err != nil {
+		if errors.Is(err, ErrWrongPassword) {
+			passwordLimiter.Allow(attemptKey, time.Now())
+		}
+		writeError(w, err)
+		return
+	}
+
+	writeJSON(w, http.StatusOK, RoomMessageResponse{
+		RoomCode: roomCode,
+		Message:  "Bot removed",
+	})
+}
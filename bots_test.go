@@ -0,0 +1,216 @@
+package main
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+	"strings"
+	"testing"
+	"time"
+)
+
+// waitFor polls cond until it holds or a few seconds pass
+func waitFor(t *testing.T, what string, cond func() bool) {
+	t.Helper()
+	deadline := time.Now().Add(3 * time.Second)
+	for !cond() {
+		if time.Now().After(deadline) {
+			t.Fatalf("Timed out waiting for %s", what)
+		}
+		time.Sleep(5 * time.Millisecond)
+	}
+}
+
+func countGuessed(state *GameStateResponse) int {
+	n := 0
+	for _, row := range state.Grid {
+		for _, cell := range row {
+			if cell.GuessedCorrectly {
+				n++
+			}
+		}
+	}
+	return n
+}
+
+func TestBotFillsGame(t *testing.T) {
+	ClearRooms()
+	defer ClearRooms()
+	CreateRoom("BOTTEST", 5, "Alice")
+
+	name, err := AddBot("bottest", "Alice", "", NewScriptedStrategy(true, true), BotOptions{ThinkTime: time.Millisecond})
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	if name != "Bot 1" {
+		t.Errorf("Expected Bot 1, got %q", name)
+	}
+
+	// The bot counts towards the two players needed to start
+	if err := StartGame("BOTTEST"); err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+
+	// The bot plays its script, then passes
+	waitFor(t, "the bot to guess two cards", func() bool {
+		state, err := GetGameState("BOTTEST", "Alice")
+		return err == nil && countGuessed(state) == 2
+	})
+	time.Sleep(20 * time.Millisecond)
+	state, _ := GetGameState("BOTTEST", "Alice")
+	if got := countGuessed(state); got != 2 {
+		t.Errorf("Expected the bot to stop after its script, got %d guesses", got)
+	}
+
+	if err := RemoveBot("BOTTEST", "Alice", "", "bot 1"); err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	if !errors.Is(RemoveBot("BOTTEST", "Alice", "", "Bot 1"), ErrBotNotFound) {
+		t.Error("Expected ErrBotNotFound for a removed bot")
+	}
+	state, _ = GetGameState("BOTTEST", "Alice")
+	if len(state.Players) != 1 {
+		t.Errorf("Expected only Alice to remain, got %v", state.Players)
+	}
+}
+
+func TestBotStopsWithRoom(t *testing.T) {
+	ClearRooms()
+	defer ClearRooms()
+	CreateRoom("BOTGONE", 5, "Alice")
+	AddBot("BOTGONE", "Alice", "", DiscardStrategy{}, BotOptions{ThinkTime: time.Millisecond})
+	AddBot("BOTGONE", "Alice", "", DiscardStrategy{}, BotOptions{ThinkTime: time.Millisecond})
+	if got := len(RoomBots("BOTGONE")); got != 2 {
+		t.Fatalf("Expected 2 bots, got %d", got)
+	}
+
+	if err := DeleteRoom("BOTGONE"); err != nil {
+		t.Fatal(err)
+	}
+	waitFor(t, "the bots to stop", func() bool { return len(RoomBots("BOTGONE")) == 0 })
+}
+
+func TestBotLimit(t *testing.T) {
+	ClearRooms()
+	defer ClearRooms()
+	defer func(max int) { config.MaxPlayersPerRoom = max }(config.MaxPlayersPerRoom)
+	config.MaxPlayersPerRoom = 0
+	CreateRoom("BOTCAP", 5, "Alice")
+
+	// Rooms without a player limit still cap their bots
+	for i := 0; i < maxBotsPerRoom; i++ {
+		if _, err := AddBot("BOTCAP", "Alice", "", DiscardStrategy{}, BotOptions{ThinkTime: time.Minute}); err != nil {
+			t.Fatalf("Expected bot %d to be added, got %v", i+1, err)
+		}
+	}
+	if _, err := AddBot("BOTCAP", "Alice", "", DiscardStrategy{}, BotOptions{ThinkTime: time.Minute}); !errors.Is(err, ErrTooManyBots) {
+		t.Errorf("Expected ErrTooManyBots, got %v", err)
+	}
+
+	// Removing a bot frees its place
+	if err := RemoveBot("BOTCAP", "Alice", "", "Bot 1"); err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	if _, err := AddBot("BOTCAP", "Alice", "", DiscardStrategy{}, BotOptions{ThinkTime: time.Minute}); err != nil {
+		t.Errorf("Expected a bot to be added after one left, got %v", err)
+	}
+}
+
+func TestAddBotHandler(t *testing.T) {
+	ClearRooms()
+	defer ClearRooms()
+	mux := newRouter()
+	CreateRoom("BOTAPI", 5, "Alice")
+
+	rec := serve(mux, http.MethodPost, "/api/v1/rooms/botapi/bots", `{"playerName": "alice", "strategy": "discard", "thinkMs": 1}`)
+	if rec.Code != http.StatusCreated {
+		t.Fatalf("Expected 201, got %d: %s", rec.Code, rec.Body)
+	}
+	var added AddBotResponse
+	json.NewDecoder(rec.Body).Decode(&added)
+	if added.RoomCode != "BOTAPI" || added.BotName != "Bot 1" || added.Strategy != "discard" {
+		t.Errorf("Unexpected response %+v", added)
+	}
+
+	rec = serve(mux, http.MethodPost, "/api/v1/rooms/BOTAPI/bots", `{"playerName": "Alice", "strategy": "psychic"}`)
+	if rec.Code != http.StatusBadRequest {
+		t.Fatalf("Expected 400, got %d", rec.Code)
+	}
+	var errResp ErrorResponse
+	json.NewDecoder(rec.Body).Decode(&errResp)
+	if errResp.Code != CodeUnknownStrategy {
+		t.Errorf("Expected %s, got %s", CodeUnknownStrategy, errResp.Code)
+	}
+
+	// Only players seated in the room may add or remove its bots
+	for body, want := range map[string]string{
+		`{}`:                        CodePlayerNameRequired,
+		`{"playerName": "Mallory"}`: CodePlayerNotFound,
+	} {
+		for _, path := range []string{"POST /api/v1/rooms/BOTAPI/bots", "DELETE /api/v1/rooms/BOTAPI/bots/Bot%201"} {
+			method, path, _ := strings.Cut(path, " ")
+			rec := serve(mux, method, path, body)
+			var got ErrorResponse
+			json.NewDecoder(rec.Body).Decode(&got)
+			if got.Code != want {
+				t.Errorf("%s %s %s: expected %s, got %d %s", method, path, body, want, rec.Code, got.Code)
+			}
+		}
+	}
+	if got := RoomBots("BOTAPI"); len(got) != 1 {
+		t.Errorf("Expected the bot to remain, got %v", got)
+	}
+
+	rec = serve(mux, http.MethodDelete, "/api/v1/rooms/BOTAPI/bots/Bot%201", `{"playerName": "Alice"}`)
+	if rec.Code != http.StatusOK {
+		t.Errorf("Expected 200, got %d: %s", rec.Code, rec.Body)
+	}
+	rec = serve(mux, http.MethodDelete, "/api/v1/rooms/BOTAPI/bots/Bot%201", `{"playerName": "Alice"}`)
+	if rec.Code != http.StatusNotFound {
+		t.Errorf("Expected 404, got %d", rec.Code)
+	}
+}
+
+func TestBotPrivateRoom(t *testing.T) {
+	ClearRooms()
+	defer ClearRooms()
+	CreateRoomWithOptions("BOTHUSH", "Alice", RoomOptions{GridSize: 5, Password: "sesame"})
+	opts := BotOptions{ThinkTime: time.Millisecond}
+
+	if _, err := AddBot("BOTHUSH", "Alice", "", DiscardStrategy{}, opts); !errors.Is(err, ErrWrongPassword) {
+		t.Errorf("Expected ErrWrongPassword without the password, got %v", err)
+	}
+	name, err := AddBot("BOTHUSH", "Alice", "sesame", DiscardStrategy{}, opts)
+	if err != nil {
+		t.Fatalf("Expected a seated player with the password to add a bot, got %v", err)
+	}
+	if !errors.Is(RemoveBot("BOTHUSH", "Alice", "wrong", name), ErrWrongPassword) {
+		t.Error("Expected ErrWrongPassword removing a bot with the wrong password")
+	}
+	if err := RemoveBot("BOTHUSH", "Alice", "sesame", name); err != nil {
+		t.Errorf("Expected no error, got %v", err)
+	}
+
+	// Humans cannot pass themselves off as bots
+	if _, err := JoinRoomWithPassword("BOTHUSH", "bot 7", "sesame"); !errors.Is(err, ErrPlayerNameReserved) {
+		t.Errorf("Expected ErrPlayerNameReserved, got %v", err)
+	}
+}
+
+func TestClearRoomsWaitsForBots(t *testing.T) {
+	ClearRooms()
+	CreateRoom("BOTCLEAR", 5, "Alice")
+	AddBot("BOTCLEAR", "Alice", "", DiscardStrategy{}, BotOptions{ThinkTime: time.Millisecond})
+	StartGame("BOTCLEAR")
+
+	botsMu.Lock()
+	b := bots["BOTCLEAR"]["Bot 1"]
+	botsMu.Unlock()
+
+	ClearRooms()
+	select {
+	case <-b.done:
+	default:
+		t.Error("Expected the bot to have stopped when ClearRooms returned")
+	}
+}
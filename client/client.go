@@ -0,0 +1,385 @@
+// Package client is a Go client for the CrossClues HTTP API.
+//
+//	c := client.New("http://localhost:8080")
+//	room, err := c.CreateRoom(ctx, client.CreateRoomRequest{PlayerName: "Alice"})
+//	if errors.Is(err, client.ErrTooManyRooms) {
+//		...
+//	}
+//
+// Requests the server rejected without acting on them (rate limited or
+// shutting down) are retried with backoff, as are GET requests that fail
+// with a network error. The server has no streaming endpoints; poll State
+// to follow a game.
+package client
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// Default retry behaviour
+const (
+	DefaultMaxRetries = 3
+	DefaultBackoff    = 200 * time.Millisecond
+	maxBackoff        = 5 * time.Second
+)
+
+// Client calls the CrossClues API. It is safe for concurrent use.
+type Client struct {
+	baseURL     string
+	httpClient  *http.Client
+	maxRetries  int
+	backoff     time.Duration
+	adminSecret string
+}
+
+// Option configures a Client
+type Option func(*Client)
+
+// WithHTTPClient sets the HTTP client used for requests
+func WithHTTPClient(hc *http.Client) Option {
+	return func(c *Client) { c.httpClient = hc }
+}
+
+// WithRetries sets how many times a transient failure is retried and the
+// initial backoff, which doubles after each attempt. A Retry-After header
+// from the server takes precedence over the backoff.
+func WithRetries(maxRetries int, backoff time.Duration) Option {
+	return func(c *Client) {
+		c.maxRetries = maxRetries
+		c.backoff = backoff
+	}
+}
+
+// WithAdminSecret sets the bearer token sent to the admin endpoints
+func WithAdminSecret(secret string) Option {
+	return func(c *Client) { c.adminSecret = secret }
+}
+
+// New returns a client for the server at baseURL, e.g.
+// "http://localhost:8080"
+func New(baseURL string, opts ...Option) *Client {
+	c := &Client{
+		baseURL:    strings.TrimSuffix(baseURL, "/") + "/api/v1",
+		httpClient: http.DefaultClient,
+		maxRetries: DefaultMaxRetries,
+		backoff:    DefaultBackoff,
+	}
+	for _, opt := range opts {
+		opt(c)
+	}
+	return c
+}
+
+// Rooms
+
+// CreateRoom creates a room with req.PlayerName as its first player
+func (c *Client) CreateRoom(ctx context.Context, req CreateRoomRequest) (*CreateRoomResponse, error) {
+	var resp CreateRoomResponse
+	if err := c.do(ctx, http.MethodPost, "/rooms", req, &resp, false); err != nil {
+		return nil, err
+	}
+	return &resp, nil
+}
+
+// JoinRoom adds a player to a room
+func (c *Client) JoinRoom(ctx context.Context, roomCode string, req JoinRoomRequest) (*JoinRoomResponse, error) {
+	var resp JoinRoomResponse
+	if err := c.do(ctx, http.MethodPost, roomPath(roomCode, "join"), req, &resp, false); err != nil {
+		return nil, err
+	}
+	return &resp, nil
+}
+
+// LeaveRoom removes a player from a room
+func (c *Client) LeaveRoom(ctx context.Context, roomCode, playerName string) (*RoomMessageResponse, error) {
+	var resp RoomMessageResponse
+	req := JoinRoomRequest{PlayerName: playerName}
+	if err := c.do(ctx, http.MethodPost, roomPath(roomCode, "leave"), req, &resp, false); err != nil {
+		return nil, err
+	}
+	return &resp, nil
+}
+
+// StartGame starts, or restarts, the game in a room
+func (c *Client) StartGame(ctx context.Context, roomCode string) (*StartGameResponse, error) {
+	var resp StartGameResponse
+	if err := c.do(ctx, http.MethodPost, roomPath(roomCode, "start"), nil, &resp, false); err != nil {
+		return nil, err
+	}
+	return &resp, nil
+}
+
+// Guess records whether the team guessed one of the player's cards
+func (c *Client) Guess(ctx context.Context, roomCode string, req GuessRequest) (*GuessResponse, error) {
+	var resp GuessResponse
+	if err := c.do(ctx, http.MethodPost, roomPath(roomCode, "guess"), req, &resp, false); err != nil {
+		return nil, err
+	}
+	return &resp, nil
+}
+
+// State returns the game state as seen by a player
+func (c *Client) State(ctx context.Context, roomCode, playerName string) (*GameStateResponse, error) {
+	var resp GameStateResponse
+	path := roomPath(roomCode, "state") + "?playerName=" + url.QueryEscape(playerName)
+	if err := c.do(ctx, http.MethodGet, path, nil, &resp, false); err != nil {
+		return nil, err
+	}
+	return &resp, nil
+}
+
+// SuggestClue asks the server for a clue for a card in the player's hand
+func (c *Client) SuggestClue(ctx context.Context, roomCode, playerName string, row, column int) (*ClueResponse, error) {
+	var resp ClueResponse
+	query := url.Values{
+		"playerName": {playerName},
+		"row":        {strconv.Itoa(row)},
+		"column":     {strconv.Itoa(column)},
+	}
+	if err := c.do(ctx, http.MethodGet, roomPath(roomCode, "clue")+"?"+query.Encode(), nil, &resp, false); err != nil {
+		return nil, err
+	}
+	return &resp, nil
+}
+
+// MatchClue ranks a room's open cells by how well they fit a clue. With all
+// set, guessed and discarded cells are ranked too. playerName must be seated
+// in the room; password is needed only for private rooms.
+func (c *Client) MatchClue(ctx context.Context, roomCode, playerName, password, clue string, all bool) (*ClueMatchResponse, error) {
+	var resp ClueMatchResponse
+	query := url.Values{"playerName": {playerName}, "clue": {clue}}
+	if password != "" {
+		query.Set("password", password)
+	}
+	if all {
+		query.Set("all", "true")
+	}
+	if err := c.do(ctx, http.MethodGet, roomPath(roomCode, "matches")+"?"+query.Encode(), nil, &resp, false); err != nil {
+		return nil, err
+	}
+	return &resp, nil
+}
+
+// History returns the finished games of a room, oldest first. playerName
+// must be seated in the room; password is needed only for private rooms.
+func (c *Client) History(ctx context.Context, roomCode, playerName, password string) (*GameHistoryResponse, error) {
+	var resp GameHistoryResponse
+	query := url.Values{"playerName": {playerName}}
+	if password != "" {
+		query.Set("password", password)
+	}
+	if err := c.do(ctx, http.MethodGet, roomPath(roomCode, "history")+"?"+query.Encode(), nil, &resp, false); err != nil {
+		return nil, err
+	}
+	return &resp, nil
+}
+
+// Share returns a spoiler-free summary of the last game finished in a room.
+// playerName must be seated in the room; password is needed only for
+// private rooms.
+func (c *Client) Share(ctx context.Context, roomCode, playerName, password string) (*ShareResponse, error) {
+	var resp ShareResponse
+	query := url.Values{"playerName": {playerName}}
+	if password != "" {
+		query.Set("password", password)
+	}
+	if err := c.do(ctx, http.MethodGet, roomPath(roomCode, "share")+"?"+query.Encode(), nil, &resp, false); err != nil {
+		return nil, err
+	}
+	return &resp, nil
+}
+
+// AddBot seats a server-side bot player in a room. req.PlayerName must be a
+// player seated in the room.
+func (c *Client) AddBot(ctx context.Context, roomCode string, req AddBotRequest) (*AddBotResponse, error) {
+	var resp AddBotResponse
+	if err := c.do(ctx, http.MethodPost, roomPath(roomCode, "bots"), req, &resp, false); err != nil {
+		return nil, err
+	}
+	return &resp, nil
+}
+
+// RemoveBot removes a bot from a room, on behalf of a player seated in it
+func (c *Client) RemoveBot(ctx context.Context, roomCode, botName string, req RemoveBotRequest) error {
+	return c.do(ctx, http.MethodDelete, roomPath(roomCode, "bots/"+url.PathEscape(botName)), req, nil, false)
+}
+
+// Lobby
+
+// Lobby lists the public rooms whose game hasn't started
+func (c *Client) Lobby(ctx context.Context) (*LobbyResponse, error) {
+	var resp LobbyResponse
+	if err := c.do(ctx, http.MethodGet, "/lobby", nil, &resp, false); err != nil {
+		return nil, err
+	}
+	return &resp, nil
+}
+
+// QuickJoin joins the best open public room, or creates one
+func (c *Client) QuickJoin(ctx context.Context, req QuickJoinRequest) (*QuickJoinResponse, error) {
+	var resp QuickJoinResponse
+	if err := c.do(ctx, http.MethodPost, "/lobby/quick-join", req, &resp, false); err != nil {
+		return nil, err
+	}
+	return &resp, nil
+}
+
+// Admin, see WithAdminSecret
+
+// AdminListRooms returns a summary of every room
+func (c *Client) AdminListRooms(ctx context.Context) (*AdminRoomListResponse, error) {
+	var resp AdminRoomListResponse
+	if err := c.do(ctx, http.MethodGet, "/admin/rooms", nil, &resp, true); err != nil {
+		return nil, err
+	}
+	return &resp, nil
+}
+
+// AdminRoom returns the full state of a room, including hidden fields
+func (c *Client) AdminRoom(ctx context.Context, roomCode string) (*AdminRoomDetail, error) {
+	var resp AdminRoomDetail
+	if err := c.do(ctx, http.MethodGet, "/admin"+roomPath(roomCode, ""), nil, &resp, true); err != nil {
+		return nil, err
+	}
+	return &resp, nil
+}
+
+// AdminDeleteRoom deletes a room
+func (c *Client) AdminDeleteRoom(ctx context.Context, roomCode string) error {
+	return c.do(ctx, http.MethodDelete, "/admin"+roomPath(roomCode, ""), nil, nil, true)
+}
+
+// AdminEndGame force-ends the game in a room
+func (c *Client) AdminEndGame(ctx context.Context, roomCode string) error {
+	return c.do(ctx, http.MethodPost, "/admin"+roomPath(roomCode, "end"), nil, nil, true)
+}
+
+// AdminRemovePlayer removes a player from a room
+func (c *Client) AdminRemovePlayer(ctx context.Context, roomCode, playerName string) error {
+	path := "/admin" + roomPath(roomCode, "players/"+url.PathEscape(playerName))
+	return c.do(ctx, http.MethodDelete, path, nil, nil, true)
+}
+
+func roomPath(roomCode, action string) string {
+	path := "/rooms/" + url.PathEscape(roomCode)
+	if action != "" {
+		path += "/" + action
+	}
+	return path
+}
+
+// do sends a request, retrying transient failures, and decodes a successful
+// response into out (if non-nil)
+func (c *Client) do(ctx context.Context, method, path string, in, out any, admin bool) error {
+	var body []byte
+	if in != nil {
+		var err error
+		if body, err = json.Marshal(in); err != nil {
+			return fmt.Errorf("encoding request: %w", err)
+		}
+	}
+
+	backoff := c.backoff
+	for attempt := 0; ; attempt++ {
+		wait, err := c.send(ctx, method, path, body, out, admin)
+		if err == nil {
+			return nil
+		}
+		if wait < 0 || attempt >= c.maxRetries {
+			return err
+		}
+
+		if wait == 0 {
+			wait = backoff
+			backoff = min(2*backoff, maxBackoff)
+		}
+		timer := time.NewTimer(wait)
+		select {
+		case <-ctx.Done():
+			timer.Stop()
+			return errors.Join(err, ctx.Err())
+		case <-timer.C:
+		}
+	}
+}
+
+// send makes a single attempt. On failure it also returns how long to wait
+// before retrying: negative if the request must not be retried, zero to use
+// the client's backoff.
+func (c *Client) send(ctx context.Context, method, path string, body []byte, out any, admin bool) (time.Duration, error) {
+	var reader io.Reader
+	if body != nil {
+		reader = bytes.NewReader(body)
+	}
+	req, err := http.NewRequestWithContext(ctx, method, c.baseURL+path, reader)
+	if err != nil {
+		return -1, err
+	}
+	if body != nil {
+		req.Header.Set("Content-Type", "application/json")
+	}
+	req.Header.Set("Accept", "application/json")
+	if admin && c.adminSecret != "" {
+		req.Header.Set("Authorization", "Bearer "+c.adminSecret)
+	}
+
+	resp, err := c.httpClient.Do(req)
+	if err != nil {
+		// A POST may have reached the server, so only GETs are retried
+		if method == http.MethodGet && ctx.Err() == nil {
+			return 0, err
+		}
+		return -1, err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode >= 400 {
+		apiErr := decodeError(resp)
+		if !apiErr.transient() {
+			return -1, apiErr
+		}
+		return retryAfter(resp.Header.Get("Retry-After")), apiErr
+	}
+
+	if out == nil {
+		io.Copy(io.Discard, resp.Body)
+		return 0, nil
+	}
+	if err := json.NewDecoder(resp.Body).Decode(out); err != nil {
+		return -1, fmt.Errorf("decoding response: %w", err)
+	}
+	return 0, nil
+}
+
+func decodeError(resp *http.Response) *Error {
+	apiErr := &Error{StatusCode: resp.StatusCode, Message: http.StatusText(resp.StatusCode)}
+	var body ErrorResponse
+	if err := json.NewDecoder(resp.Body).Decode(&body); err == nil {
+		apiErr.Code = body.Code
+		apiErr.Details = body.Details
+		apiErr.RequestID = body.RequestID
+		if body.Error != "" {
+			apiErr.Message = body.Error
+		}
+	}
+	return apiErr
+}
+
+// retryAfter parses a Retry-After header given in seconds
+func retryAfter(header string) time.Duration {
+	seconds, err := strconv.Atoi(header)
+	if err != nil || seconds < 0 {
+		return 0
+	}
+	return time.Duration(seconds) * time.Second
+}
@@ -0,0 +1,86 @@
+package client
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+)
+
+func TestErrorDecoding(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		w.WriteHeader(http.StatusNotFound)
+		w.Write([]byte(`{"error": "room not found", "code": "ROOM_NOT_FOUND", "requestId": "abc"}`))
+	}))
+	defer srv.Close()
+
+	_, err := New(srv.URL).StartGame(context.Background(), "NOPE")
+	if !errors.Is(err, ErrRoomNotFound) {
+		t.Fatalf("Expected ErrRoomNotFound, got %v", err)
+	}
+	var apiErr *Error
+	if !errors.As(err, &apiErr) || apiErr.StatusCode != http.StatusNotFound || apiErr.RequestID != "abc" {
+		t.Errorf("Expected a 404 *Error with the request ID, got %#v", err)
+	}
+}
+
+func TestRetryTransient(t *testing.T) {
+	calls := 0
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		calls++
+		if calls < 3 {
+			w.WriteHeader(http.StatusTooManyRequests)
+			w.Write([]byte(`{"error": "too many requests", "code": "RATE_LIMITED"}`))
+			return
+		}
+		w.Write([]byte(`{"roomCode": "ABC", "message": "Game started"}`))
+	}))
+	defer srv.Close()
+
+	c := New(srv.URL, WithRetries(3, time.Millisecond))
+	resp, err := c.StartGame(context.Background(), "ABC")
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	if resp.RoomCode != "ABC" || calls != 3 {
+		t.Errorf("Expected success on the third call, got %+v after %d calls", resp, calls)
+	}
+}
+
+func TestNoRetryOnClientError(t *testing.T) {
+	calls := 0
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		calls++
+		w.WriteHeader(http.StatusBadRequest)
+		w.Write([]byte(`{"error": "game is already over", "code": "GAME_OVER"}`))
+	}))
+	defer srv.Close()
+
+	c := New(srv.URL, WithRetries(3, time.Millisecond))
+	_, err := c.Guess(context.Background(), "ABC", GuessRequest{PlayerName: "Alice"})
+	if !errors.Is(err, ErrGameOver) {
+		t.Errorf("Expected ErrGameOver, got %v", err)
+	}
+	if calls != 1 {
+		t.Errorf("Expected 1 call, got %d", calls)
+	}
+}
+
+func TestRetryStopsOnContextCancel(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusServiceUnavailable)
+		w.Write([]byte(`{"error": "server is shutting down", "code": "SHUTTING_DOWN"}`))
+	}))
+	defer srv.Close()
+
+	ctx, cancel := context.WithTimeout(context.Background(), 20*time.Millisecond)
+	defer cancel()
+	c := New(srv.URL, WithRetries(100, time.Second))
+	_, err := c.Lobby(ctx)
+	if !errors.Is(err, context.DeadlineExceeded) || !errors.Is(err, ErrShuttingDown) {
+		t.Errorf("Expected the last error and the context error, got %v", err)
+	}
+}
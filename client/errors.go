@@ -0,0 +1,126 @@
+package client
+
+import (
+	"fmt"
+	"net/http"
+)
+
+// Error is an error response from the server. Compare against the sentinel
+// errors below with errors.Is, which matches on Code.
+type Error struct {
+	StatusCode int
+	Code       string
+	Message    string
+	Details    map[string]any
+	RequestID  string
+}
+
+func (e *Error) Error() string {
+	if e.StatusCode == 0 {
+		return e.Message
+	}
+	return fmt.Sprintf("%s (%d %s)", e.Message, e.StatusCode, e.Code)
+}
+
+// Is matches any *Error with the same code
+func (e *Error) Is(target error) bool {
+	t, ok := target.(*Error)
+	return ok && t.Code == e.Code
+}
+
+// Error codes, matching errors.go in the server
+const (
+	CodeRoomNotFound       = "ROOM_NOT_FOUND"
+	CodeRoomExists         = "ROOM_EXISTS"
+	CodeRoomFull           = "ROOM_FULL"
+	CodeTooManyRooms       = "TOO_MANY_ROOMS"
+	CodeNoFreeRoomCode     = "NO_FREE_ROOM_CODE"
+	CodeInvalidRoomCode    = "INVALID_ROOM_CODE"
+	CodeUnknownWordPack    = "UNKNOWN_WORD_PACK"
+	CodeInvalidGridSize    = "INVALID_GRID_SIZE"
+	CodeInvalidMaxPlayers  = "INVALID_MAX_PLAYERS"
+	CodeInvalidPassword    = "INVALID_PASSWORD"
+	CodeWrongPassword      = "WRONG_PASSWORD"
+	CodePlayerExists       = "PLAYER_EXISTS"
+	CodePlayerNotFound     = "PLAYER_NOT_FOUND"
+	CodePlayerNameRequired = "PLAYER_NAME_REQUIRED"
+	CodePlayerNameTooLong  = "PLAYER_NAME_TOO_LONG"
+	CodePlayerNameInvalid  = "PLAYER_NAME_INVALID"
+	CodePlayerNameReserved = "PLAYER_NAME_RESERVED"
+	CodeGameNotStarted     = "GAME_NOT_STARTED"
+	CodeGameOver           = "GAME_OVER"
+	CodeNotEnoughPlayers   = "NOT_ENOUGH_PLAYERS"
+	CodeNoCard             = "NO_CARD"
+	CodeBotNotFound        = "BOT_NOT_FOUND"
+	CodeTooManyBots        = "TOO_MANY_BOTS"
+	CodeUnknownStrategy    = "UNKNOWN_STRATEGY"
+	CodeNoClue             = "NO_CLUE"
+	CodeInvalidClue        = "INVALID_CLUE"
+	CodeInvalidCell        = "INVALID_CELL"
+	CodeInvalidFormat      = "INVALID_FORMAT"
+	CodeNoFinishedGame     = "NO_FINISHED_GAME"
+	CodeInvalidRequest     = "INVALID_REQUEST"
+	CodeNotFound           = "NOT_FOUND"
+	CodeMethodNotAllowed   = "METHOD_NOT_ALLOWED"
+	CodeUnauthorized       = "UNAUTHORIZED"
+	CodeRateLimited        = "RATE_LIMITED"
+	CodeShuttingDown       = "SHUTTING_DOWN"
+	CodeInternal           = "INTERNAL"
+)
+
+// Sentinel errors for use with errors.Is
+var (
+	ErrRoomNotFound       = &Error{Code: CodeRoomNotFound, Message: "room not found"}
+	ErrRoomExists         = &Error{Code: CodeRoomExists, Message: "room already exists"}
+	ErrRoomFull           = &Error{Code: CodeRoomFull, Message: "room is full"}
+	ErrTooManyRooms       = &Error{Code: CodeTooManyRooms, Message: "server has reached its room limit"}
+	ErrNoFreeRoomCode     = &Error{Code: CodeNoFreeRoomCode, Message: "no free room code was found"}
+	ErrInvalidRoomCode    = &Error{Code: CodeInvalidRoomCode, Message: "invalid room code"}
+	ErrUnknownWordPack    = &Error{Code: CodeUnknownWordPack, Message: "unknown word pack"}
+	ErrInvalidGridSize    = &Error{Code: CodeInvalidGridSize, Message: "invalid grid size"}
+	ErrInvalidMaxPlayers  = &Error{Code: CodeInvalidMaxPlayers, Message: "invalid max players"}
+	ErrInvalidPassword    = &Error{Code: CodeInvalidPassword, Message: "invalid room password"}
+	ErrWrongPassword      = &Error{Code: CodeWrongPassword, Message: "incorrect room password"}
+	ErrPlayerExists       = &Error{Code: CodePlayerExists, Message: "player name already taken in this room"}
+	ErrPlayerNotFound     = &Error{Code: CodePlayerNotFound, Message: "player not found in this room"}
+	ErrPlayerNameRequired = &Error{Code: CodePlayerNameRequired, Message: "player name is required"}
+	ErrPlayerNameTooLong  = &Error{Code: CodePlayerNameTooLong, Message: "player name is too long"}
+	ErrPlayerNameInvalid  = &Error{Code: CodePlayerNameInvalid, Message: "player name contains invalid characters"}
+	ErrPlayerNameReserved = &Error{Code: CodePlayerNameReserved, Message: "player names starting with \"Bot \" are reserved for bots"}
+	ErrGameNotStarted     = &Error{Code: CodeGameNotStarted, Message: "game has not started"}
+	ErrGameOver           = &Error{Code: CodeGameOver, Message: "game is already over"}
+	ErrNotEnoughPlayers   = &Error{Code: CodeNotEnoughPlayers, Message: "not enough players to start"}
+	ErrNoCard             = &Error{Code: CodeNoCard, Message: "player does not have a card for this cell"}
+	ErrBotNotFound        = &Error{Code: CodeBotNotFound, Message: "bot not found in this room"}
+	ErrTooManyBots        = &Error{Code: CodeTooManyBots, Message: "room has reached its bot limit"}
+	ErrUnknownStrategy    = &Error{Code: CodeUnknownStrategy, Message: "unknown bot strategy"}
+	ErrNoClue             = &Error{Code: CodeNoClue, Message: "no clue fits this card"}
+	ErrInvalidClue        = &Error{Code: CodeInvalidClue, Message: "clue must be a single word of at most 32 characters"}
+	ErrInvalidCell        = &Error{Code: CodeInvalidCell, Message: "invalid row or column"}
+	ErrInvalidFormat      = &Error{Code: CodeInvalidFormat, Message: "format must be json or csv"}
+	ErrNoFinishedGame     = &Error{Code: CodeNoFinishedGame, Message: "no game has finished in this room yet"}
+	ErrInvalidRequest     = &Error{Code: CodeInvalidRequest, Message: "invalid request"}
+	ErrNotFound           = &Error{Code: CodeNotFound, Message: "endpoint not found"}
+	ErrMethodNotAllowed   = &Error{Code: CodeMethodNotAllowed, Message: "method not allowed"}
+	ErrUnauthorized       = &Error{Code: CodeUnauthorized, Message: "unauthorized"}
+	ErrRateLimited        = &Error{Code: CodeRateLimited, Message: "rate limited"}
+	ErrShuttingDown       = &Error{Code: CodeShuttingDown, Message: "server is shutting down"}
+	ErrInternal           = &Error{Code: CodeInternal, Message: "internal server error"}
+)
+
+// transient reports whether a request that failed with this error may
+// succeed if repeated. The server rejected these before acting on them, so
+// they are safe to retry even for non-idempotent requests.
+func (e *Error) transient() bool {
+	switch e.Code {
+	case CodeRateLimited, CodeShuttingDown:
+		return true
+	case "":
+		// Not from the API itself, e.g. a proxy in front of it
+		switch e.StatusCode {
+		case http.StatusBadGateway, http.StatusServiceUnavailable, http.StatusGatewayTimeout, http.StatusTooManyRequests:
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,233 @@
+package client
+
+import "time"
+
+// Wire types. These mirror schema.go in the server; a test in the server
+// package fails if the two drift apart.
+
+type Card struct {
+	Row    int `json:"row"`
+	Column int `json:"column"`
+}
+
+type CellResponse struct {
+	GuessedCorrectly bool `json:"guessedCorrectly"`
+	DiscardedByMe    bool `json:"discardedByMe"`
+}
+
+type CreateRoomRequest struct {
+	RoomCode   string `json:"roomCode,omitempty"` // Optional; generated by the server if empty
+	GridSize   int    `json:"gridSize"`
+	WordPack   string `json:"wordPack,omitempty"`
+	Password   string `json:"password,omitempty"` // Optional; makes the room private
+	Public     bool   `json:"public,omitempty"`   // List the room in the lobby
+	MaxPlayers int    `json:"maxPlayers,omitempty"`
+	PlayerName string `json:"playerName"`
+}
+
+type CreateRoomResponse struct {
+	RoomCode   string `json:"roomCode"`
+	PlayerName string `json:"playerName"`
+	Message    string `json:"message"`
+}
+
+type JoinRoomRequest struct {
+	PlayerName string `json:"playerName"`
+	Password   string `json:"password,omitempty"`
+}
+
+type JoinRoomResponse struct {
+	RoomCode   string `json:"roomCode"`
+	PlayerName string `json:"playerName"`
+	CardsDealt int    `json:"cardsDealt"`
+	Message    string `json:"message"`
+}
+
+type StartGameResponse struct {
+	RoomCode string `json:"roomCode"`
+	Message  string `json:"message"`
+}
+
+type RoomMessageResponse struct {
+	RoomCode string `json:"roomCode"`
+	Message  string `json:"message"`
+}
+
+type GuessRequest struct {
+	PlayerName string `json:"playerName"`
+	Row        int    `json:"row"`
+	Column     int    `json:"column"`
+	Correct    bool   `json:"correct"`
+}
+
+type GuessResponse struct {
+	RoomCode string `json:"roomCode"`
+	Message  string `json:"message"`
+	GameOver bool   `json:"gameOver"`
+}
+
+type GameStateResponse struct {
+	RoomCode       string           `json:"roomCode"`
+	GridSize       int              `json:"gridSize"`
+	GameStarted    bool             `json:"gameStarted"`
+	GameOver       bool             `json:"gameOver"`
+	CorrectGuesses int              `json:"correctGuesses"`
+	TotalCells     int              `json:"totalCells"`
+	RowWords       []string         `json:"rowWords"`
+	ColumnWords    []string         `json:"columnWords"`
+	PlayerCards    []Card           `json:"playerCards"`
+	Grid           [][]CellResponse `json:"grid"`
+	Players        []string         `json:"players"`
+	Share          string           `json:"share,omitempty"` // Shareable summary, once the game is over
+}
+
+type ClueResponse struct {
+	RoomCode     string   `json:"roomCode"`
+	Row          int      `json:"row"`
+	Column       int      `json:"column"`
+	RowWord      string   `json:"rowWord"`
+	ColumnWord   string   `json:"columnWord"`
+	Clue         string   `json:"clue"`
+	Alternatives []string `json:"alternatives"` // Runner-up clues, best first
+}
+
+type CellMatch struct {
+	Row        int     `json:"row"`
+	Column     int     `json:"column"`
+	RowWord    string  `json:"rowWord"`
+	ColumnWord string  `json:"columnWord"`
+	Score      float64 `json:"score"` // 0 to 1
+	Resolved   bool    `json:"resolved,omitempty"`
+}
+
+type ClueMatchResponse struct {
+	RoomCode string      `json:"roomCode"`
+	Clue     string      `json:"clue"`
+	Cells    []CellMatch `json:"cells"` // Best fit first
+}
+
+type CellRecord struct {
+	Row         int    `json:"row"`
+	Column      int    `json:"column"`
+	RowWord     string `json:"rowWord"`
+	ColumnWord  string `json:"columnWord"`
+	Outcome     string `json:"outcome"` // guessed, discarded or unresolved
+	DiscardedBy string `json:"discardedBy,omitempty"`
+}
+
+// GameRecord is a finished game, kept after the room moves on to the next one
+type GameRecord struct {
+	Game        int          `json:"game"` // 1 for the room's first finished game
+	RoomCode    string       `json:"roomCode"`
+	GridSize    int          `json:"gridSize"`
+	WordPack    string       `json:"wordPack"`
+	Players     []string     `json:"players"` // Players in the room when the game ended
+	RowWords    []string     `json:"rowWords"`
+	ColumnWords []string     `json:"columnWords"`
+	Cells       []CellRecord `json:"cells"` // Row by row
+	Score       int          `json:"score"` // Cells guessed correctly
+	TotalCells  int          `json:"totalCells"`
+	EndedEarly  bool         `json:"endedEarly,omitempty"` // Ended before every cell was resolved
+	StartedAt   time.Time    `json:"startedAt"`
+	EndedAt     time.Time    `json:"endedAt"`
+}
+
+type GameHistoryResponse struct {
+	RoomCode string       `json:"roomCode"`
+	Games    []GameRecord `json:"games"` // Oldest first
+}
+
+// ShareResponse is a spoiler-free summary of a finished game for pasting
+// into chat
+type ShareResponse struct {
+	RoomCode string `json:"roomCode"`
+	Game     int    `json:"game"` // As numbered in the room's history
+	Text     string `json:"text"`
+	Link     string `json:"link"` // Back to the room, also the last line of Text
+}
+
+type AddBotRequest struct {
+	PlayerName string `json:"playerName"`         // A player seated in the room
+	Password   string `json:"password,omitempty"` // Required for private rooms
+	Strategy   string `json:"strategy,omitempty"` // random (default), discard, scripted or clue
+	Script     []bool `json:"script,omitempty"`   // Outcomes for the scripted strategy
+	ThinkMs    int    `json:"thinkMs,omitempty"`  // Average delay before each move
+}
+
+type AddBotResponse struct {
+	RoomCode string `json:"roomCode"`
+	BotName  string `json:"botName"`
+	Strategy string `json:"strategy"`
+}
+
+type RemoveBotRequest struct {
+	PlayerName string `json:"playerName"`         // A player seated in the room
+	Password   string `json:"password,omitempty"` // Required for private rooms
+}
+
+type LobbyRoom struct {
+	RoomCode    string `json:"roomCode"`
+	PlayerCount int    `json:"playerCount"`
+	MaxPlayers  int    `json:"maxPlayers"` // 0 means unlimited
+	GridSize    int    `json:"gridSize"`
+	WordPack    string `json:"wordPack"`
+	Private     bool   `json:"private"`
+	AgeSeconds  int64  `json:"ageSeconds"`
+}
+
+type LobbyResponse struct {
+	Rooms []LobbyRoom `json:"rooms"`
+}
+
+type QuickJoinRequest struct {
+	PlayerName string `json:"playerName"`
+	GridSize   int    `json:"gridSize,omitempty"`
+	WordPack   string `json:"wordPack,omitempty"`
+}
+
+type QuickJoinResponse struct {
+	RoomCode   string `json:"roomCode"`
+	PlayerName string `json:"playerName"`
+	CardsDealt int    `json:"cardsDealt"`
+	Created    bool   `json:"created"`
+	Message    string `json:"message"`
+}
+
+type ErrorResponse struct {
+	Error     string         `json:"error"`
+	Code      string         `json:"code"`
+	Details   map[string]any `json:"details,omitempty"`
+	RequestID string         `json:"requestId,omitempty"`
+}
+
+// Admin types
+
+type AdminRoomSummary struct {
+	RoomCode    string    `json:"roomCode"`
+	Players     []string  `json:"players"`
+	GridSize    int       `json:"gridSize"`
+	GameStarted bool      `json:"gameStarted"`
+	GameOver    bool      `json:"gameOver"`
+	Public      bool      `json:"public"`
+	Private     bool      `json:"private"`
+	CreatedAt   time.Time `json:"createdAt"`
+	AgeSeconds  int64     `json:"ageSeconds"`
+}
+
+type AdminRoomListResponse struct {
+	Rooms []AdminRoomSummary `json:"rooms"`
+}
+
+type AdminCell struct {
+	GuessedCorrectly bool   `json:"guessedCorrectly"`
+	DiscardedBy      string `json:"discardedBy"`
+}
+
+type AdminRoomDetail struct {
+	AdminRoomSummary
+	RowWords    []string          `json:"rowWords"`
+	ColumnWords []string          `json:"columnWords"`
+	Grid        [][]AdminCell     `json:"grid"`
+	CardDeck    []Card            `json:"cardDeck"`
+	PlayerHands map[string][]Card `json:"playerHands"`
+}
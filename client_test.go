@@ -0,0 +1,150 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"net/http/httptest"
+	"reflect"
+	"sort"
+	"testing"
+
+	"github.com/dfturn/crossclues2/client"
+)
+
+// TestClientTypes checks the client package's wire types against schema.go
+func TestClientTypes(t *testing.T) {
+	pairs := map[string][2]reflect.Type{}
+	for name, typ := range specTypes {
+		pairs[name] = [2]reflect.Type{typ, nil}
+	}
+	clientTypes := []any{
+		client.Card{}, client.CellResponse{}, client.CreateRoomRequest{}, client.CreateRoomResponse{},
+		client.JoinRoomRequest{}, client.JoinRoomResponse{}, client.StartGameResponse{},
+		client.RoomMessageResponse{}, client.GuessRequest{}, client.GuessResponse{},
+		client.GameStateResponse{}, client.ClueResponse{}, client.CellMatch{}, client.ClueMatchResponse{},
+		client.CellRecord{}, client.GameRecord{}, client.GameHistoryResponse{}, client.ShareResponse{},
+		client.AddBotRequest{}, client.AddBotResponse{}, client.RemoveBotRequest{}, client.LobbyRoom{}, client.LobbyResponse{},
+		client.QuickJoinRequest{}, client.QuickJoinResponse{}, client.ErrorResponse{},
+		client.AdminRoomSummary{}, client.AdminRoomListResponse{}, client.AdminCell{}, client.AdminRoomDetail{},
+	}
+	for _, v := range clientTypes {
+		typ := reflect.TypeOf(v)
+		p := pairs[typ.Name()]
+		p[1] = typ
+		pairs[typ.Name()] = p
+	}
+
+	for name, p := range pairs {
+		if p[0] == nil || p[1] == nil {
+			t.Errorf("%s exists on only one side of the server/client boundary", name)
+			continue
+		}
+		compareWireTypes(t, name, p[0], p[1])
+	}
+}
+
+func compareWireTypes(t *testing.T, where string, server, client reflect.Type) {
+	t.Helper()
+	if server.Kind() != client.Kind() {
+		t.Errorf("%s: server is %s, client is %s", where, server, client)
+		return
+	}
+	switch server.Kind() {
+	case reflect.Slice, reflect.Map:
+		compareWireTypes(t, where+"[]", server.Elem(), client.Elem())
+	case reflect.Struct:
+		if server == timeType || client == timeType {
+			if server != client {
+				t.Errorf("%s: server is %s, client is %s", where, server, client)
+			}
+			return
+		}
+		sf, cf := jsonFields(server), jsonFields(client)
+		for name, st := range sf {
+			ct, ok := cf[name]
+			if !ok {
+				t.Errorf("%s.%s is missing from the client", where, name)
+				continue
+			}
+			compareWireTypes(t, where+"."+name, st, ct)
+		}
+		for name := range cf {
+			if _, ok := sf[name]; !ok {
+				t.Errorf("%s.%s is in the client but not the server", where, name)
+			}
+		}
+	}
+}
+
+func TestClientErrorCodes(t *testing.T) {
+	var codes []string
+	for code := range codeStatus {
+		codes = append(codes, code)
+	}
+	sort.Strings(codes)
+
+	var clientCodes []string
+	for _, err := range []*client.Error{
+		client.ErrRoomNotFound, client.ErrRoomExists, client.ErrRoomFull, client.ErrTooManyRooms, client.ErrNoFreeRoomCode,
+		client.ErrInvalidRoomCode, client.ErrUnknownWordPack, client.ErrInvalidGridSize,
+		client.ErrInvalidMaxPlayers, client.ErrInvalidPassword, client.ErrWrongPassword,
+		client.ErrPlayerExists, client.ErrPlayerNotFound, client.ErrPlayerNameRequired,
+		client.ErrPlayerNameTooLong, client.ErrPlayerNameInvalid, client.ErrPlayerNameReserved, client.ErrGameNotStarted,
+		client.ErrGameOver, client.ErrNotEnoughPlayers, client.ErrNoCard, client.ErrBotNotFound,
+		client.ErrTooManyBots, client.ErrUnknownStrategy, client.ErrNoClue, client.ErrInvalidClue, client.ErrInvalidCell,
+		client.ErrInvalidFormat, client.ErrNoFinishedGame, client.ErrInvalidRequest, client.ErrNotFound, client.ErrMethodNotAllowed,
+		client.ErrUnauthorized, client.ErrRateLimited, client.ErrShuttingDown, client.ErrInternal,
+	} {
+		clientCodes = append(clientCodes, err.Code)
+	}
+	sort.Strings(clientCodes)
+
+	if !reflect.DeepEqual(codes, clientCodes) {
+		t.Errorf("Client error codes are out of date:\nserver: %v\nclient: %v", codes, clientCodes)
+	}
+}
+
+func TestClientAgainstServer(t *testing.T) {
+	ClearRooms()
+	srv := httptest.NewServer(newRouter())
+	defer srv.Close()
+
+	ctx := context.Background()
+	c := client.New(srv.URL)
+
+	created, err := c.CreateRoom(ctx, client.CreateRoomRequest{PlayerName: "Alice", GridSize: 3})
+	if err != nil {
+		t.Fatalf("CreateRoom: %v", err)
+	}
+	code := created.RoomCode
+
+	if _, err := c.StartGame(ctx, code); !errors.Is(err, client.ErrNotEnoughPlayers) {
+		t.Errorf("Expected ErrNotEnoughPlayers, got %v", err)
+	}
+	if _, err := c.JoinRoom(ctx, code, client.JoinRoomRequest{PlayerName: "Bob"}); err != nil {
+		t.Fatalf("JoinRoom: %v", err)
+	}
+	if _, err := c.StartGame(ctx, code); err != nil {
+		t.Fatalf("StartGame: %v", err)
+	}
+
+	state, err := c.State(ctx, code, "Bob")
+	if err != nil {
+		t.Fatalf("State: %v", err)
+	}
+	card := state.PlayerCards[0]
+	guess, err := c.Guess(ctx, code, client.GuessRequest{PlayerName: "Bob", Row: card.Row, Column: card.Column, Correct: true})
+	if err != nil {
+		t.Fatalf("Guess: %v", err)
+	}
+	if guess.RoomCode != code {
+		t.Errorf("Expected room code %s, got %s", code, guess.RoomCode)
+	}
+
+	if _, err := c.LeaveRoom(ctx, code, "Bob"); err != nil {
+		t.Errorf("LeaveRoom: %v", err)
+	}
+	if _, err := c.State(ctx, "NOPE", "Bob"); !errors.Is(err, client.ErrRoomNotFound) {
+		t.Errorf("Expected ErrRoomNotFound, got %v", err)
+	}
+}
@@ -0,0 +1,426 @@
+package main
+
+import (
+	"bufio"
+	"bytes"
+	_ "embed"
+	"errors"
+	"fmt"
+	"io"
+	"math"
+	"math/rand"
+	"net/http"
+	"os"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+	"unicode"
+	"unicode/utf8"
+)
+
+// Clue suggestions and guesses. A good clue for a card fits both its row and
+// column words and as few of the other open cells as possible; a guesser
+// picks the open cell a clue fits best. How well a clue fits a word comes
+// from an association list: associations.txt is built in, and
+// -associations-file can add entries for custom word packs.
+
+// associationsData is the built-in association list
+//
+//go:embed associations.txt
+var associationsData []byte
+
+const (
+	// maxClueAlternatives is how many runner-up clues a suggestion lists
+	maxClueAlternatives = 3
+
+	// maxClueLength bounds the clues the guesser accepts
+	maxClueLength = 32
+
+	// rivalPenalty weighs how well a clue fits other open cells against how
+	// well it fits the target. Below 1 so that, when every clue is shared
+	// with another cell, stronger associations still rank first.
+	rivalPenalty = 0.5
+)
+
+// associationModel maps an upper-case board word to its clues and their
+// strength, from 1 for the strongest down to 0.5
+type associationModel map[string]map[string]float64
+
+// associations is the active model. It is populated at startup and
+// read-only afterwards.
+var associations = mustParseAssociations(associationsData)
+
+func mustParseAssociations(data []byte) associationModel {
+	m, err := parseAssociations(bytes.NewReader(data), "associations.txt")
+	if err != nil {
+		panic(err)
+	}
+	return m
+}
+
+// parseAssociations reads lines of the form "WORD: clue clue ...", strongest
+// clue first. Blank lines and lines starting with # are ignored.
+func parseAssociations(r io.Reader, name string) (associationModel, error) {
+	m := make(associationModel)
+	scanner := bufio.NewScanner(r)
+	for line := 1; scanner.Scan(); line++ {
+		text := strings.TrimSpace(scanner.Text())
+		if text == "" || strings.HasPrefix(text, "#") {
+			continue
+		}
+		word, list, ok := strings.Cut(text, ":")
+		word = strings.ToUpper(strings.TrimSpace(word))
+		if !ok || word == "" {
+			return nil, fmt.Errorf("associations %s line %d: expected WORD: clues", name, line)
+		}
+
+		clues := strings.Fields(strings.ToLower(list))
+		strengths := make(map[string]float64, len(clues))
+		for i, clue := range clues {
+			if _, seen := strengths[clue]; !seen {
+				strengths[clue] = 1 - 0.5*float64(i)/float64(len(clues))
+			}
+		}
+		m[word] = strengths
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, fmt.Errorf("reading associations %s: %w", name, err)
+	}
+	return m, nil
+}
+
+// loadAssociations adds the entries in path to the active model, replacing
+// any built-in entry for the same word
+func loadAssociations(path string) error {
+	f, err := os.Open(path)
+	if err != nil {
+		return err
+	}
+	defer f.Close()
+
+	extra, err := parseAssociations(f, path)
+	if err != nil {
+		return err
+	}
+	for word, clues := range extra {
+		associations[word] = clues
+	}
+	return nil
+}
+
+// unassociated returns the words with no clues tied to them in either
+// direction. Clues cannot be suggested or matched for them.
+func (m associationModel) unassociated(words []string) []string {
+	var missing []string
+	for _, word := range words {
+		if len(m.candidates(word)) == 0 {
+			missing = append(missing, word)
+		}
+	}
+	return missing
+}
+
+// strength is how strongly a clue is tied to a word, in either direction:
+// the clue may be listed under the word, or the word under the clue
+func (m associationModel) strength(word, clue string) float64 {
+	return math.Max(m[strings.ToUpper(word)][clue], m[strings.ToUpper(clue)][strings.ToLower(word)])
+}
+
+// candidates returns the single-word clues tied to a word
+func (m associationModel) candidates(word string) []string {
+	word = strings.ToUpper(word)
+	var clues []string
+	for clue := range m[word] {
+		clues = append(clues, clue)
+	}
+	for other, list := range m {
+		if _, ok := list[strings.ToLower(word)]; ok && !strings.Contains(other, " ") {
+			clues = append(clues, strings.ToLower(other))
+		}
+	}
+	return clues
+}
+
+// fit scores how well a clue describes the cell at the crossing of two
+// words. Clues tied to both words score 0.5-1; clues tied to only one score
+// at most 0.3.
+func (m associationModel) fit(rowWord, columnWord, clue string) float64 {
+	r := m.strength(rowWord, clue)
+	c := m.strength(columnWord, clue)
+	if r > 0 && c > 0 {
+		return math.Sqrt(r * c)
+	}
+	return 0.3 * math.Max(r, c)
+}
+
+// clueBoard is the part of a game a clue is judged against
+type clueBoard struct {
+	RowWords    []string
+	ColumnWords []string
+	Open        []Card // Cells not yet guessed or discarded
+}
+
+// legalClue reports whether clue avoids every word on the board. A clue
+// that contains a board word, or is part of one, gives too much away.
+func (b clueBoard) legalClue(clue string) bool {
+	for _, words := range [][]string{b.RowWords, b.ColumnWords} {
+		for _, word := range words {
+			for _, part := range strings.Fields(strings.ToLower(word)) {
+				if strings.Contains(clue, part) || strings.Contains(part, clue) {
+					return false
+				}
+			}
+		}
+	}
+	return true
+}
+
+type scoredClue struct {
+	clue  string
+	fit   float64 // How well the clue fits the target cell
+	rival float64 // How well it fits the best other open cell
+	ties  int     // Other open cells it fits at least as well as the target
+}
+
+func (c scoredClue) score() float64 { return c.fit - rivalPenalty*c.rival }
+
+// unambiguous reports whether a guesser would pick the target for this clue
+func (c scoredClue) unambiguous() bool { return c.ties == 0 }
+
+// rankClues returns the legal clues tied to the target cell, best first
+func (m associationModel) rankClues(board clueBoard, target Card) []scoredClue {
+	rowWord, columnWord := board.RowWords[target.Row], board.ColumnWords[target.Column]
+
+	var ranked []scoredClue
+	seen := make(map[string]bool)
+	for _, word := range []string{rowWord, columnWord} {
+		for _, clue := range m.candidates(word) {
+			if seen[clue] || !board.legalClue(clue) {
+				continue
+			}
+			seen[clue] = true
+
+			sc := scoredClue{clue: clue, fit: m.fit(rowWord, columnWord, clue)}
+			for _, cell := range board.Open {
+				if cell == target {
+					continue
+				}
+				f := m.fit(board.RowWords[cell.Row], board.ColumnWords[cell.Column], clue)
+				sc.rival = math.Max(sc.rival, f)
+				if f >= sc.fit {
+					sc.ties++
+				}
+			}
+			ranked = append(ranked, sc)
+		}
+	}
+
+	sort.Slice(ranked, func(i, j int) bool {
+		if si, sj := ranked[i].score(), ranked[j].score(); si != sj {
+			return si > sj
+		}
+		return ranked[i].clue < ranked[j].clue
+	})
+	return ranked
+}
+
+// rankCells scores every cell against a clue, best fit first. It is the
+// guesser's view of the board: ties keep row-major order.
+func (m associationModel) rankCells(board clueBoard, clue string, cells []Card) []CellMatch {
+	matches := make([]CellMatch, len(cells))
+	for i, cell := range cells {
+		rowWord, columnWord := board.RowWords[cell.Row], board.ColumnWords[cell.Column]
+		matches[i] = CellMatch{
+			Row:        cell.Row,
+			Column:     cell.Column,
+			RowWord:    rowWord,
+			ColumnWord: columnWord,
+			Score:      math.Round(m.fit(rowWord, columnWord, clue)*1000) / 1000,
+		}
+	}
+	sort.SliceStable(matches, func(i, j int) bool { return matches[i].Score > matches[j].Score })
+	return matches
+}
+
+// guess picks the open cell a guesser would choose for a clue, at random
+// among those that fit it equally well
+func (m associationModel) guess(board clueBoard, clue string) Card {
+	ranked := m.rankCells(board, clue, board.Open)
+	top := 1
+	for top < len(ranked) && ranked[top].Score == ranked[0].Score {
+		top++
+	}
+	pick := ranked[rand.Intn(top)]
+	return Card{Row: pick.Row, Column: pick.Column}
+}
+
+// openCells returns the cells that are neither guessed nor discarded.
+// Callers must hold r.mu.
+func (r *Room) openCells() []Card {
+	var open []Card
+	for row := 0; row < r.GridSize; row++ {
+		for col := 0; col < r.GridSize; col++ {
+			if cell := r.Grid[row][col]; !cell.GuessedCorrectly && cell.DiscardedBy == "" {
+				open = append(open, Card{Row: row, Column: col})
+			}
+		}
+	}
+	return open
+}
+
+// SuggestClue proposes a clue for a card in the player's hand
+func SuggestClue(roomCode, playerName string, row, col int) (*ClueResponse, error) {
+	room, exists := getRoom(roomCode)
+	if !exists {
+		return nil, ErrRoomNotFound
+	}
+
+	room.mu.RLock()
+	defer room.mu.RUnlock()
+
+	if !room.GameStarted {
+		return nil, ErrGameNotStarted
+	}
+	if room.GameOver {
+		return nil, ErrGameOver
+	}
+	player, ok := room.findPlayer(playerName)
+	if !ok {
+		return nil, ErrPlayerNotFound
+	}
+	if !room.HasCard(player, row, col) {
+		return nil, ErrNoCard
+	}
+
+	board := clueBoard{RowWords: room.RowWords, ColumnWords: room.ColumnWords, Open: room.openCells()}
+	ranked := associations.rankClues(board, Card{Row: row, Column: col})
+	if len(ranked) == 0 {
+		return nil, ErrNoClue
+	}
+
+	resp := &ClueResponse{
+		RoomCode:     room.RoomCode,
+		Row:          row,
+		Column:       col,
+		RowWord:      room.RowWords[row],
+		ColumnWord:   room.ColumnWords[col],
+		Clue:         ranked[0].clue,
+		Alternatives: []string{},
+	}
+	for _, alt := range ranked[1:] {
+		if len(resp.Alternatives) == maxClueAlternatives {
+			break
+		}
+		resp.Alternatives = append(resp.Alternatives, alt.clue)
+	}
+	return resp, nil
+}
+
+// normalizeClue lower-cases a clue and checks that it is a single word
+func normalizeClue(clue string) (string, error) {
+	clue = strings.ToLower(strings.TrimSpace(clue))
+	if clue == "" || utf8.RuneCountInString(clue) > maxClueLength || strings.ContainsFunc(clue, unicode.IsSpace) {
+		return "", ErrInvalidClue
+	}
+	return clue, nil
+}
+
+// MatchClue ranks a room's open cells by how well they fit a clue. With
+// includeResolved, guessed and discarded cells are ranked too, for looking
+// back at a finished game. playerName must be seated in the room, and
+// password is needed if the room is private.
+func MatchClue(roomCode, playerName, password, clue string, includeResolved bool) (*ClueMatchResponse, error) {
+	clue, err := normalizeClue(clue)
+	if err != nil {
+		return nil, err
+	}
+	room, exists := getRoom(roomCode)
+	if !exists {
+		return nil, ErrRoomNotFound
+	}
+	if err := checkSeated(room, playerName, password); err != nil {
+		return nil, err
+	}
+
+	room.mu.RLock()
+	defer room.mu.RUnlock()
+
+	board := clueBoard{RowWords: room.RowWords, ColumnWords: room.ColumnWords, Open: room.openCells()}
+	cells := board.Open
+	if includeResolved {
+		cells = nil
+		for row := 0; row < room.GridSize; row++ {
+			for col := 0; col < room.GridSize; col++ {
+				cells = append(cells, Card{Row: row, Column: col})
+			}
+		}
+	}
+
+	matches := associations.rankCells(board, clue, cells)
+	for i := range matches {
+		cell := room.Grid[matches[i].Row][matches[i].Column]
+		matches[i].Resolved = cell.GuessedCorrectly || cell.DiscardedBy != ""
+	}
+	return &ClueMatchResponse{RoomCode: room.RoomCode, Clue: clue, Cells: matches}, nil
+}
+
+// HTTP Handlers
+
+func handleSuggestClue(w http.ResponseWriter, r *http.Request) {
+	roomCode := normalizeRoomCode(r.PathValue("code"))
+
+	query := r.URL.Query()
+	playerName := query.Get("playerName")
+	if playerName == "" {
+		writeError(w, ErrPlayerNameRequired)
+		return
+	}
+	logPlayer(r, playerName)
+
+	row, rowErr := strconv.Atoi(query.Get("row"))
+	col, colErr := strconv.Atoi(query.Get("column"))
+	if rowErr != nil || colErr != nil {
+		writeError(w, ErrInvalidCell)
+		return
+	}
+
+	suggestion, err := SuggestClue(roomCode, playerName, row, col)
+	if err != nil {
+		writeError(w, err)
+		return
+	}
+
+	writeJSON(w, http.StatusOK, suggestion)
+}
+
+func handleMatchClue(w http.ResponseWriter, r *http.Request) {
+	roomCode := normalizeRoomCode(r.PathValue("code"))
+
+	query := r.URL.Query()
+	playerName := query.Get("playerName")
+	if playerName == "" {
+		writeError(w, ErrPlayerNameRequired)
+		return
+	}
+	logPlayer(r, playerName)
+
+	// Wrong passwords count against the same limit as joins
+	attemptKey := roomCode + "|" + clientIP(r)
+	if blocked, wait := passwordLimiter.Blocked(attemptKey, time.Now()); blocked {
+		writeRateLimited(w, wait)
+		return
+	}
+
+	includeResolved, _ := strconv.ParseBool(query.Get("all"))
+	matches, err := MatchClue(roomCode, playerName, query.Get("password"), query.Get("clue"), includeResolved)
+	if err != nil {
+		if errors.Is(err, ErrWrongPassword) {
+			passwordLimiter.Allow(attemptKey, time.Now())
+		}
+		writeError(w, err)
+		return
+	}
+
+	writeJSON(w, http.StatusOK, matches)
+}
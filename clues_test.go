@@ -0,0 +1,261 @@
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"strings"
+	"testing"
+)
+
+func TestAssociationsCoverWordList(t *testing.T) {
+	for _, word := range wordList {
+		if len(associations[word]) == 0 {
+			t.Errorf("associations.txt has no clues for %s", word)
+		}
+	}
+}
+
+func TestParseAssociations(t *testing.T) {
+	m, err := parseAssociations(strings.NewReader("# comment\n\nsnow: Winter cold\nPALM TREE: beach\n"), "test")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if m["SNOW"]["winter"] != 1 || m["SNOW"]["cold"] != 0.75 {
+		t.Errorf("Unexpected strengths %v", m["SNOW"])
+	}
+	if m["PALM TREE"]["beach"] != 1 {
+		t.Errorf("Expected multi-word entries, got %v", m)
+	}
+
+	if _, err := parseAssociations(strings.NewReader("SNOW winter\n"), "test"); err == nil {
+		t.Error("Expected an error for a line without a colon")
+	}
+}
+
+func TestUnassociated(t *testing.T) {
+	m, _ := parseAssociations(strings.NewReader("SNOW: winter cold\nBEACH: sand palm\n"), "test")
+	// PALM has no entry of its own but is listed under BEACH
+	got := m.unassociated([]string{"SNOW", "PALM", "ZEBRA", "QUARK"})
+	if want := []string{"ZEBRA", "QUARK"}; fmt.Sprint(got) != fmt.Sprint(want) {
+		t.Errorf("Expected %v, got %v", want, got)
+	}
+}
+
+func TestRankClues(t *testing.T) {
+	m := associationModel{
+		"SNOW":  {"white": 1, "cold": 0.9},
+		"WOLF":  {"howl": 1, "cold": 0.8},
+		"MILK":  {"white": 1, "cow": 0.9},
+		"NIGHT": {"howl": 0.6},
+	}
+	board := clueBoard{
+		RowWords:    []string{"SNOW", "MILK"},
+		ColumnWords: []string{"WOLF", "NIGHT"},
+		Open:        []Card{{0, 0}, {0, 1}, {1, 0}, {1, 1}},
+	}
+
+	// "cold" fits SNOW x WOLF alone; "howl" also fits WOLF's other cell
+	ranked := m.rankClues(board, Card{Row: 0, Column: 0})
+	if len(ranked) == 0 || ranked[0].clue != "cold" {
+		t.Fatalf("Expected cold first, got %v", ranked)
+	}
+	if !ranked[0].unambiguous() {
+		t.Errorf("Expected cold to point at its cell, got %+v", ranked[0])
+	}
+	for _, c := range ranked {
+		if c.clue == "howl" && c.unambiguous() {
+			t.Errorf("Expected howl to be ambiguous, got %+v", c)
+		}
+	}
+
+	// Once the rival cell is resolved, it no longer counts against a clue
+	board.Open = []Card{{0, 0}}
+	for _, c := range m.rankClues(board, Card{Row: 0, Column: 0}) {
+		if !c.unambiguous() {
+			t.Errorf("Expected %s to fit only its own cell, got %+v", c.clue, c)
+		}
+	}
+}
+
+func TestNormalizeClue(t *testing.T) {
+	for clue, want := range map[string]error{
+		" Cold ":                nil,
+		strings.Repeat("é", 32): nil, // 64 bytes, but 32 characters
+		strings.Repeat("a", 33): ErrInvalidClue,
+		"":                      ErrInvalidClue,
+		"two words":             ErrInvalidClue,
+	} {
+		if _, err := normalizeClue(clue); err != want {
+			t.Errorf("normalizeClue(%q) = %v, want %v", clue, err, want)
+		}
+	}
+}
+
+func TestLegalClue(t *testing.T) {
+	board := clueBoard{RowWords: []string{"SNOW", "PALM TREE"}, ColumnWords: []string{"ICE"}}
+	for clue, want := range map[string]bool{
+		"snowman": false,
+		"tree":    false,
+		"no":      false,
+		"ice":     false,
+		"cold":    true,
+	} {
+		if got := board.legalClue(clue); got != want {
+			t.Errorf("legalClue(%q) = %v, want %v", clue, got, want)
+		}
+	}
+}
+
+func TestSuggestClue(t *testing.T) {
+	ClearRooms()
+	CreateRoom("CLUETEST", 5, "Alice")
+	JoinRoom("CLUETEST", "Bob")
+
+	if _, err := SuggestClue("CLUETEST", "Alice", 0, 0); err != ErrGameNotStarted {
+		t.Errorf("Expected ErrGameNotStarted, got %v", err)
+	}
+	StartGame("CLUETEST")
+
+	state, _ := GetGameState("CLUETEST", "Alice")
+	card := state.PlayerCards[0]
+	mux := newRouter()
+	rec := serve(mux, http.MethodGet, fmt.Sprintf("/api/v1/rooms/cluetest/clue?playerName=alice&row=%d&column=%d", card.Row, card.Column), "")
+	if rec.Code != http.StatusOK {
+		t.Fatalf("Expected 200, got %d: %s", rec.Code, rec.Body)
+	}
+	var resp ClueResponse
+	json.NewDecoder(rec.Body).Decode(&resp)
+	if resp.RowWord != state.RowWords[card.Row] || resp.ColumnWord != state.ColumnWords[card.Column] {
+		t.Errorf("Expected the card's words, got %s and %s", resp.RowWord, resp.ColumnWord)
+	}
+	board := clueBoard{RowWords: state.RowWords, ColumnWords: state.ColumnWords}
+	for _, clue := range append([]string{resp.Clue}, resp.Alternatives...) {
+		if !board.legalClue(clue) {
+			t.Errorf("Suggested clue %q gives away a board word", clue)
+		}
+	}
+
+	// Only cards in the player's hand can be clued
+	bob, _ := GetGameState("CLUETEST", "Bob")
+	rec = serve(mux, http.MethodGet, fmt.Sprintf("/api/v1/rooms/CLUETEST/clue?playerName=Alice&row=%d&column=%d", bob.PlayerCards[0].Row, bob.PlayerCards[0].Column), "")
+	if rec.Code != http.StatusBadRequest {
+		t.Errorf("Expected 400 for another player's card, got %d", rec.Code)
+	}
+	rec = serve(mux, http.MethodGet, "/api/v1/rooms/CLUETEST/clue?playerName=Alice&row=A", "")
+	if rec.Code != http.StatusBadRequest {
+		t.Errorf("Expected 400 for a bad cell, got %d", rec.Code)
+	}
+}
+
+func TestRankCells(t *testing.T) {
+	m := associationModel{
+		"SNOW":  {"white": 1, "cold": 0.9},
+		"WOLF":  {"howl": 1, "cold": 0.8},
+		"NIGHT": {"howl": 0.6},
+	}
+	board := clueBoard{
+		RowWords:    []string{"SNOW", "MILK"},
+		ColumnWords: []string{"WOLF", "NIGHT"},
+		Open:        []Card{{0, 0}, {0, 1}, {1, 0}, {1, 1}},
+	}
+
+	ranked := m.rankCells(board, "cold", board.Open)
+	if ranked[0].Row != 0 || ranked[0].Column != 0 {
+		t.Errorf("Expected SNOW x WOLF first, got %+v", ranked[0])
+	}
+	if ranked[3].Score != 0 {
+		t.Errorf("Expected MILK x NIGHT to score 0, got %+v", ranked[3])
+	}
+	if got := m.guess(board, "cold"); got != (Card{Row: 0, Column: 0}) {
+		t.Errorf("Expected the guesser to pick A1, got %v", got)
+	}
+
+	// "howl" fits both WOLF cells equally; the guesser picks one of them
+	for i := 0; i < 20; i++ {
+		if got := m.guess(board, "howl"); got.Column != 0 {
+			t.Fatalf("Expected a WOLF cell, got %v", got)
+		}
+	}
+}
+
+func TestMatchClue(t *testing.T) {
+	ClearRooms()
+	CreateRoom("MATCHTEST", 3, "Alice")
+	JoinRoom("MATCHTEST", "Bob")
+	StartGame("MATCHTEST")
+
+	state, _ := GetGameState("MATCHTEST", "Alice")
+	card := state.PlayerCards[0]
+	SubmitGuess("MATCHTEST", "Alice", card.Row, card.Column, true)
+
+	mux := newRouter()
+	rec := serve(mux, http.MethodGet, "/api/v1/rooms/matchtest/matches?playerName=alice&clue=%20Cold%20", "")
+	if rec.Code != http.StatusOK {
+		t.Fatalf("Expected 200, got %d: %s", rec.Code, rec.Body)
+	}
+	var resp ClueMatchResponse
+	json.NewDecoder(rec.Body).Decode(&resp)
+	if resp.Clue != "cold" || len(resp.Cells) != 8 {
+		t.Fatalf("Expected 8 open cells for cold, got %q and %d", resp.Clue, len(resp.Cells))
+	}
+	for i, cell := range resp.Cells {
+		if cell.Row == card.Row && cell.Column == card.Column {
+			t.Error("Expected the guessed cell to be left out")
+		}
+		if i > 0 && cell.Score > resp.Cells[i-1].Score {
+			t.Errorf("Expected cells best first, got %v after %v", cell.Score, resp.Cells[i-1].Score)
+		}
+	}
+
+	// Resolved cells are ranked too when asked for
+	rec = serve(mux, http.MethodGet, "/api/v1/rooms/MATCHTEST/matches?playerName=Alice&clue=cold&all=true", "")
+	json.NewDecoder(rec.Body).Decode(&resp)
+	resolved := 0
+	for _, cell := range resp.Cells {
+		if cell.Resolved {
+			resolved++
+		}
+	}
+	if len(resp.Cells) != 9 || resolved != 1 {
+		t.Errorf("Expected 9 cells with 1 resolved, got %d and %d", len(resp.Cells), resolved)
+	}
+
+	for _, clue := range []string{"", "two%20words"} {
+		rec = serve(mux, http.MethodGet, "/api/v1/rooms/MATCHTEST/matches?playerName=Alice&clue="+clue, "")
+		if rec.Code != http.StatusBadRequest {
+			t.Errorf("Expected 400 for clue %q, got %d", clue, rec.Code)
+		}
+	}
+
+	// Only seated players may ask, with the password for a private room
+	CreateRoomWithOptions("MATCHHUSH", "Alice", RoomOptions{GridSize: 3, Password: "sesame"})
+	for path, want := range map[string]int{
+		"/api/v1/rooms/MATCHTEST/matches?clue=cold":                                  http.StatusBadRequest,
+		"/api/v1/rooms/MATCHTEST/matches?playerName=Mallory&clue=cold":               http.StatusNotFound,
+		"/api/v1/rooms/MATCHHUSH/matches?playerName=Alice&clue=cold":                 http.StatusForbidden,
+		"/api/v1/rooms/MATCHHUSH/matches?playerName=Alice&password=wrong&clue=cold":  http.StatusForbidden,
+		"/api/v1/rooms/MATCHHUSH/matches?playerName=Alice&password=sesame&clue=cold": http.StatusOK,
+	} {
+		if rec := serve(mux, http.MethodGet, path, ""); rec.Code != want {
+			t.Errorf("%s: expected %d, got %d", path, want, rec.Code)
+		}
+	}
+}
+
+func TestClueStrategy(t *testing.T) {
+	state := &GameStateResponse{
+		GameStarted: true,
+		RowWords:    []string{"SNOW", "CAMEL"},
+		ColumnWords: []string{"WINTER", "DESERT"},
+		PlayerCards: []Card{{Row: 1, Column: 1}},
+		Grid:        [][]CellResponse{{{}, {}}, {{}, {}}},
+	}
+	card, correct, ok := ClueStrategy{}.Decide(BotView{Name: "Bot 1", State: state})
+	if !ok || card != (Card{Row: 1, Column: 1}) {
+		t.Fatalf("Expected the bot to play its card, got %v %v", card, ok)
+	}
+	if !correct {
+		t.Error("Expected CAMEL x DESERT to be clued and guessed")
+	}
+}
@@ -0,0 +1,286 @@
+// Command crossclues-load puts a CrossClues server under realistic load, to
+// find how many concurrent rooms one instance can handle.
+//
+//	crossclues-load -server http://localhost:8080 -rooms 200 -players 4 -duration 5m
+//
+// Each room is created by a host and filled by the other players, who join a
+// few seconds apart. The host starts the game, and starts it again each time
+// it ends, until the run is over; then everyone leaves. Players poll their
+// state as the web frontend does, and resolve a card after a pause to think
+// of a clue and have it guessed. Rooms are started gradually over -ramp.
+//
+// Progress is printed every -report; at the end, a table gives the
+// throughput, latency percentiles and error rate of each kind of request.
+// All load comes from one client address, so run the server with its rate
+// limits disabled (-rate-create 0 -rate-join 0 -rate-action 0
+// -rate-room-join 0 -rate-room-action 0) unless they are what is being
+// measured.
+package main
+
+import (
+	"context"
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"math/rand"
+	"net/http"
+	"os"
+	"os/signal"
+	"sync"
+	"sync/atomic"
+	"syscall"
+	"time"
+
+	"github.com/dfturn/crossclues2/client"
+)
+
+// options configures a run
+type options struct {
+	rooms    int
+	players  int
+	gridSize int
+
+	duration time.Duration
+	ramp     time.Duration // Rooms are started evenly over this period
+	poll     time.Duration // State polling interval per player
+	think    time.Duration // Average pause before resolving a card
+	correct  float64       // Share of cards guessed rather than discarded
+	timeout  time.Duration // Per request
+}
+
+func main() {
+	server := flag.String("server", envOr("CROSSCLUES_SERVER", "http://localhost:8080"), "server URL")
+	var opts options
+	flag.IntVar(&opts.rooms, "rooms", 10, "number of concurrent rooms")
+	flag.IntVar(&opts.players, "players", 4, "players per room, at least 2")
+	flag.IntVar(&opts.gridSize, "grid", 0, "grid size (default: server default)")
+	flag.DurationVar(&opts.duration, "duration", time.Minute, "how long to run")
+	flag.DurationVar(&opts.ramp, "ramp", 10*time.Second, "period over which rooms are started")
+	flag.DurationVar(&opts.poll, "poll", time.Second, "state polling interval per player")
+	flag.DurationVar(&opts.think, "think", 15*time.Second, "average pause before a player resolves a card")
+	flag.Float64Var(&opts.correct, "correct", 0.7, "share of cards guessed correctly")
+	flag.DurationVar(&opts.timeout, "timeout", 10*time.Second, "per-request timeout")
+	report := flag.Duration("report", 10*time.Second, "progress report interval, 0 to disable")
+	flag.Parse()
+
+	if opts.rooms < 1 || opts.players < 2 || opts.poll <= 0 || opts.think <= 0 {
+		fmt.Fprintln(os.Stderr, "crossclues-load: need at least 1 room, 2 players per room and positive -poll and -think")
+		os.Exit(2)
+	}
+
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+	defer stop()
+	ctx, cancel := context.WithTimeout(ctx, opts.duration)
+	defer cancel()
+
+	// One connection per simulated player, as each would be a browser
+	transport := http.DefaultTransport.(*http.Transport).Clone()
+	transport.MaxIdleConnsPerHost = opts.rooms * opts.players
+	lt := &loadTest{
+		api:   client.New(*server, client.WithHTTPClient(&http.Client{Transport: transport}), client.WithRetries(0, 0)),
+		opts:  opts,
+		stats: newStats(),
+	}
+
+	fmt.Printf("%d rooms x %d players against %s for %s\n", opts.rooms, opts.players, *server, opts.duration)
+	start := time.Now()
+	done := make(chan struct{})
+	go func() {
+		lt.run(ctx)
+		close(done)
+	}()
+
+	var tick <-chan time.Time
+	if *report > 0 {
+		ticker := time.NewTicker(*report)
+		defer ticker.Stop()
+		tick = ticker.C
+	}
+	for running := true; running; {
+		select {
+		case <-done:
+			running = false
+		case <-tick:
+			lt.progress(os.Stdout, time.Since(start))
+		}
+	}
+
+	elapsed := time.Since(start)
+	fmt.Printf("\nFinished in %s: %d rooms created, %d games finished\n\n", elapsed.Round(time.Second), lt.rooms.Load(), lt.games.Load())
+	lt.stats.report(os.Stdout, elapsed)
+}
+
+func envOr(key, fallback string) string {
+	if v := os.Getenv(key); v != "" {
+		return v
+	}
+	return fallback
+}
+
+// loadTest is one run against a server
+type loadTest struct {
+	api   *client.Client
+	opts  options
+	stats *stats
+
+	rooms atomic.Int64 // Rooms created
+	games atomic.Int64 // Games played to the end
+}
+
+// run plays every room until ctx is done, then waits for the players to
+// leave
+func (lt *loadTest) run(ctx context.Context) {
+	var wg sync.WaitGroup
+	for i := 0; i < lt.opts.rooms; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			delay := lt.opts.ramp * time.Duration(i) / time.Duration(lt.opts.rooms)
+			if sleep(ctx, delay) {
+				lt.playRoom(ctx)
+			}
+		}()
+	}
+	wg.Wait()
+}
+
+func (lt *loadTest) progress(w io.Writer, elapsed time.Duration) {
+	requests, failures := lt.stats.totals()
+	fmt.Fprintf(w, "%6s  %d requests (%.1f/s), %d errors, %d rooms, %d games finished\n",
+		elapsed.Round(time.Second), requests, float64(requests)/elapsed.Seconds(), failures, lt.rooms.Load(), lt.games.Load())
+}
+
+// call times one request and records its outcome. Requests cut short by the
+// end of the run are not counted.
+func (lt *loadTest) call(ctx context.Context, op string, fn func(ctx context.Context) error) error {
+	reqCtx, cancel := context.WithTimeout(ctx, lt.opts.timeout)
+	defer cancel()
+	start := time.Now()
+	err := fn(reqCtx)
+	if err == nil || ctx.Err() == nil {
+		lt.stats.record(op, time.Since(start), err)
+	}
+	return err
+}
+
+// playRoom creates a room and plays it with its players until ctx is done
+func (lt *loadTest) playRoom(ctx context.Context) {
+	host := "Host"
+	var code string
+	err := lt.call(ctx, "create", func(ctx context.Context) error {
+		resp, err := lt.api.CreateRoom(ctx, client.CreateRoomRequest{PlayerName: host, GridSize: lt.opts.gridSize})
+		if err == nil {
+			code = resp.RoomCode
+		}
+		return err
+	})
+	if err != nil {
+		return
+	}
+	lt.rooms.Add(1)
+
+	// Players join a few seconds apart; the host starts once they are in
+	var joined, playing sync.WaitGroup
+	for n := 2; n <= lt.opts.players; n++ {
+		joined.Add(1)
+		playing.Add(1)
+		go func() {
+			defer playing.Done()
+			name := fmt.Sprintf("Player %d", n)
+			ok := sleep(ctx, time.Duration(rand.Int63n(int64(3*time.Second)))) &&
+				lt.call(ctx, "join", func(ctx context.Context) error {
+					_, err := lt.api.JoinRoom(ctx, code, client.JoinRoomRequest{PlayerName: name})
+					return err
+				}) == nil
+			joined.Done()
+			if ok {
+				lt.play(ctx, code, name, false)
+			}
+		}()
+	}
+	joined.Wait()
+	lt.start(ctx, code)
+	lt.play(ctx, code, host, true)
+	playing.Wait()
+}
+
+func (lt *loadTest) start(ctx context.Context, code string) {
+	lt.call(ctx, "start", func(ctx context.Context) error {
+		_, err := lt.api.StartGame(ctx, code)
+		return err
+	})
+}
+
+// play is one player's session: poll, think, resolve a card, and leave at
+// the end of the run. The host also restarts finished games.
+func (lt *loadTest) play(ctx context.Context, code, name string, host bool) {
+	defer func() {
+		// The run is over, so leave on a fresh context
+		lt.call(context.Background(), "leave", func(ctx context.Context) error {
+			_, err := lt.api.LeaveRoom(ctx, code, name)
+			return err
+		})
+	}()
+
+	var moveAt time.Time // When the player resolves a card; zero while idle
+	for sleep(ctx, jitter(lt.opts.poll)) {
+		var state *client.GameStateResponse
+		err := lt.call(ctx, "state", func(ctx context.Context) error {
+			var err error
+			state, err = lt.api.State(ctx, code, name)
+			return err
+		})
+		if errors.Is(err, client.ErrRoomNotFound) || errors.Is(err, client.ErrPlayerNotFound) {
+			return
+		}
+		if err != nil {
+			continue
+		}
+
+		switch {
+		case state.GameOver && host:
+			// A short break between games, as players look at the result
+			if sleep(ctx, jitter(lt.opts.think)) {
+				lt.start(ctx, code)
+			}
+		case !state.GameStarted || state.GameOver || len(state.PlayerCards) == 0:
+			moveAt = time.Time{}
+		case moveAt.IsZero():
+			moveAt = time.Now().Add(jitter(lt.opts.think))
+		case time.Now().After(moveAt):
+			moveAt = time.Time{}
+			card := state.PlayerCards[rand.Intn(len(state.PlayerCards))]
+			lt.call(ctx, "guess", func(ctx context.Context) error {
+				resp, err := lt.api.Guess(ctx, code, client.GuessRequest{
+					PlayerName: name,
+					Row:        card.Row,
+					Column:     card.Column,
+					Correct:    rand.Float64() < lt.opts.correct,
+				})
+				if err == nil && resp.GameOver {
+					lt.games.Add(1)
+				}
+				return err
+			})
+		}
+	}
+}
+
+// jitter returns a random duration from half to one and a half times d, so
+// that players don't move in lockstep
+func jitter(d time.Duration) time.Duration {
+	return d/2 + time.Duration(rand.Int63n(int64(d)+1))
+}
+
+// sleep waits for d and reports whether ctx is still running
+func sleep(ctx context.Context, d time.Duration) bool {
+	timer := time.NewTimer(d)
+	defer timer.Stop()
+	select {
+	case <-ctx.Done():
+		return false
+	case <-timer.C:
+		return true
+	}
+}
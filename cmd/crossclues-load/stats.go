@@ -0,0 +1,199 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"math"
+	"sort"
+	"sync"
+	"text/tabwriter"
+	"time"
+
+	"github.com/dfturn/crossclues2/client"
+)
+
+// stats collects the outcome and latency of every request, by operation
+type stats struct {
+	mu  sync.Mutex
+	ops map[string]*opStats
+}
+
+type opStats struct {
+	latencies histogram      // Failed requests included
+	errors    map[string]int // Error code -> count
+}
+
+// Latencies are counted in log-spaced buckets so that memory stays fixed
+// however long a run lasts. Each bucket is about 2% wider than the one
+// below it, from 1µs up to about a minute; slower requests share the last
+// bucket.
+const (
+	histogramMin       = time.Microsecond
+	bucketsPerDoubling = 32
+	histogramBuckets   = 26 * bucketsPerDoubling
+)
+
+type histogram struct {
+	counts [histogramBuckets]int
+	n      int
+	max    time.Duration
+}
+
+func (h *histogram) add(d time.Duration) {
+	i := 0
+	if d > histogramMin {
+		i = int(math.Ceil(math.Log2(float64(d)/float64(histogramMin)) * bucketsPerDoubling))
+	}
+	h.counts[min(i, histogramBuckets-1)]++
+	h.n++
+	h.max = max(h.max, d)
+}
+
+func (h *histogram) merge(other *histogram) {
+	for i, n := range other.counts {
+		h.counts[i] += n
+	}
+	h.n += other.n
+	h.max = max(h.max, other.max)
+}
+
+// bucketLimit returns the largest latency counted in bucket i
+func bucketLimit(i int) time.Duration {
+	return time.Duration(float64(histogramMin) * math.Exp2(float64(i)/bucketsPerDoubling))
+}
+
+func newStats() *stats {
+	return &stats{ops: make(map[string]*opStats)}
+}
+
+func (s *stats) record(op string, d time.Duration, err error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	o := s.ops[op]
+	if o == nil {
+		o = &opStats{errors: make(map[string]int)}
+		s.ops[op] = o
+	}
+	o.latencies.add(d)
+	if err != nil {
+		o.errors[errorCode(err)]++
+	}
+}
+
+// errorCode classifies an error: the API error code when the server
+// answered, otherwise "TIMEOUT" or "TRANSPORT"
+func errorCode(err error) string {
+	var apiErr *client.Error
+	switch {
+	case errors.As(err, &apiErr) && apiErr.Code != "":
+		return apiErr.Code
+	case errors.As(err, &apiErr):
+		return fmt.Sprintf("HTTP_%d", apiErr.StatusCode)
+	case errors.Is(err, context.DeadlineExceeded):
+		return "TIMEOUT"
+	}
+	return "TRANSPORT"
+}
+
+// percentile returns the nearest-rank p-th percentile of the latencies: the
+// ceil(p/100*N)-th smallest, given as the upper limit of its bucket and so
+// at most about 2% high
+func percentile(h *histogram, p float64) time.Duration {
+	if h.n == 0 {
+		return 0
+	}
+	rank := max(int(math.Ceil(p/100*float64(h.n))), 1)
+	seen := 0
+	for i, n := range h.counts {
+		if seen += n; seen >= rank {
+			return min(bucketLimit(i), h.max)
+		}
+	}
+	return h.max
+}
+
+// totals returns the number of requests and failures so far
+func (s *stats) totals() (requests, failures int) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	for _, o := range s.ops {
+		requests += o.latencies.n
+		for _, n := range o.errors {
+			failures += n
+		}
+	}
+	return requests, failures
+}
+
+// report writes a table of throughput, latency percentiles and error rates
+// per operation, followed by a breakdown of the errors
+func (s *stats) report(w io.Writer, elapsed time.Duration) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	names := make([]string, 0, len(s.ops))
+	for name := range s.ops {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	tw := tabwriter.NewWriter(w, 0, 0, 2, ' ', tabwriter.AlignRight)
+	fmt.Fprintln(tw, "op\trequests\treq/s\terrors\terror %\tp50\tp90\tp99\tmax\t")
+	var all histogram
+	allErrors := make(map[string]int)
+	failures := 0
+	for _, name := range names {
+		o := s.ops[name]
+		all.merge(&o.latencies)
+
+		n := 0
+		for code, count := range o.errors {
+			n += count
+			allErrors[name+" "+code] += count
+		}
+		failures += n
+		writeRow(tw, name, &o.latencies, n, elapsed)
+	}
+	writeRow(tw, "total", &all, failures, elapsed)
+	tw.Flush()
+
+	if len(allErrors) > 0 {
+		keys := make([]string, 0, len(allErrors))
+		for key := range allErrors {
+			keys = append(keys, key)
+		}
+		sort.Strings(keys)
+		fmt.Fprintln(w, "\nErrors:")
+		for _, key := range keys {
+			fmt.Fprintf(w, "  %-40s %d\n", key, allErrors[key])
+		}
+	}
+}
+
+func writeRow(w io.Writer, name string, h *histogram, failures int, elapsed time.Duration) {
+	rate, errorRate := 0.0, 0.0
+	if elapsed > 0 {
+		rate = float64(h.n) / elapsed.Seconds()
+	}
+	if h.n > 0 {
+		errorRate = 100 * float64(failures) / float64(h.n)
+	}
+	fmt.Fprintf(w, "%s\t%d\t%.1f\t%d\t%.2f\t%s\t%s\t%s\t%s\t\n", name, h.n, rate, failures, errorRate,
+		formatLatency(percentile(h, 50)), formatLatency(percentile(h, 90)),
+		formatLatency(percentile(h, 99)), formatLatency(percentile(h, 100)))
+}
+
+// formatLatency prints a latency in milliseconds with a precision that
+// suits its size
+func formatLatency(d time.Duration) string {
+	ms := float64(d) / float64(time.Millisecond)
+	switch {
+	case ms < 10:
+		return fmt.Sprintf("%.2fms", ms)
+	case ms < 100:
+		return fmt.Sprintf("%.1fms", ms)
+	}
+	return fmt.Sprintf("%.0fms", ms)
+}
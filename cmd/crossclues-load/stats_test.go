@@ -0,0 +1,82 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/dfturn/crossclues2/client"
+)
+
+func TestPercentile(t *testing.T) {
+	var h histogram
+	for i := 100; i >= 1; i-- {
+		h.add(time.Duration(i) * time.Millisecond)
+	}
+	// Percentiles are bucket limits, so up to about 2% high
+	for p, want := range map[float64]time.Duration{
+		50:  50 * time.Millisecond,
+		90:  90 * time.Millisecond,
+		99:  99 * time.Millisecond,
+		100: 100 * time.Millisecond,
+		0:   time.Millisecond,
+	} {
+		if got := percentile(&h, p); got < want || got > want*102/100 {
+			t.Errorf("p%v = %v, want %v", p, got, want)
+		}
+	}
+	if got := percentile(&h, 100); got != 100*time.Millisecond {
+		t.Errorf("Expected the maximum to be exact, got %v", got)
+	}
+
+	// Nearest rank rounds up: p60 of four samples is the third
+	var few histogram
+	for _, ms := range []time.Duration{10, 20, 30, 40} {
+		few.add(ms * time.Millisecond)
+	}
+	if got := percentile(&few, 60); got < 30*time.Millisecond || got > 31*time.Millisecond {
+		t.Errorf("p60 of 10-40ms = %v, want 30ms", got)
+	}
+
+	if got := percentile(&histogram{}, 50); got != 0 {
+		t.Errorf("Expected 0 for no samples, got %v", got)
+	}
+}
+
+func TestErrorCode(t *testing.T) {
+	for err, want := range map[error]string{
+		&client.Error{StatusCode: 404, Code: client.CodeRoomNotFound}: client.CodeRoomNotFound,
+		&client.Error{StatusCode: 502}:                                "HTTP_502",
+		fmt.Errorf("get: %w", context.DeadlineExceeded):               "TIMEOUT",
+		errors.New("connection refused"):                              "TRANSPORT",
+	} {
+		if got := errorCode(err); got != want {
+			t.Errorf("errorCode(%v) = %s, want %s", err, got, want)
+		}
+	}
+}
+
+func TestReport(t *testing.T) {
+	s := newStats()
+	for i := 0; i < 9; i++ {
+		s.record("state", time.Millisecond, nil)
+	}
+	s.record("state", 40*time.Millisecond, &client.Error{StatusCode: 429, Code: client.CodeRateLimited})
+	s.record("guess", 2*time.Millisecond, nil)
+
+	if requests, failures := s.totals(); requests != 11 || failures != 1 {
+		t.Errorf("Expected 11 requests and 1 failure, got %d and %d", requests, failures)
+	}
+
+	var out strings.Builder
+	s.report(&out, 10*time.Second)
+	report := out.String()
+	for _, want := range []string{"state  10  1.0  1  10.00", "total  11  1.1", "40.0ms", "state RATE_LIMITED"} {
+		if !strings.Contains(strings.Join(strings.Fields(report), "  "), strings.Join(strings.Fields(want), "  ")) {
+			t.Errorf("Expected %q in the report:\n%s", want, report)
+		}
+	}
+}
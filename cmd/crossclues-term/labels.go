@@ -0,0 +1,28 @@
+package main
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/dfturn/crossclues2/client"
+	"github.com/dfturn/crossclues2/internal/board"
+)
+
+// cardLabel names a cell the way the web client does: a row letter and a
+// 1-based column number, e.g. "B3"
+func cardLabel(card client.Card) string {
+	return board.Label(card.Row, card.Column)
+}
+
+// parseLabel is the inverse of cardLabel. It accepts either case.
+func parseLabel(label string, gridSize int) (client.Card, error) {
+	label = strings.ToUpper(strings.TrimSpace(label))
+	if len(label) < 2 {
+		return client.Card{}, fmt.Errorf("%q is not a cell; use a row letter and column number like B3", label)
+	}
+	row, col, ok := board.ParseLabel(label, gridSize)
+	if !ok {
+		return client.Card{}, fmt.Errorf("%q is not a cell; use A1 to %s", label, board.Label(gridSize-1, gridSize-1))
+	}
+	return client.Card{Row: row, Column: col}, nil
+}
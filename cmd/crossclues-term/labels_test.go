@@ -0,0 +1,33 @@
+package main
+
+import (
+	"testing"
+
+	"github.com/dfturn/crossclues2/client"
+)
+
+func TestCardLabel(t *testing.T) {
+	for row := 0; row < 5; row++ {
+		for col := 0; col < 5; col++ {
+			card := client.Card{Row: row, Column: col}
+			parsed, err := parseLabel(cardLabel(card), 5)
+			if err != nil || parsed != card {
+				t.Errorf("%s: expected %+v, got %+v (%v)", cardLabel(card), card, parsed, err)
+			}
+		}
+	}
+	if got := cardLabel(client.Card{Row: 1, Column: 2}); got != "B3" {
+		t.Errorf("Expected B3, got %s", got)
+	}
+	if card, err := parseLabel(" c1 ", 3); err != nil || card != (client.Card{Row: 2, Column: 0}) {
+		t.Errorf("Expected C1 to parse case-insensitively, got %+v (%v)", card, err)
+	}
+}
+
+func TestParseLabelErrors(t *testing.T) {
+	for _, label := range []string{"", "B", "3B", "D1", "A0", "A4", "AA"} {
+		if _, err := parseLabel(label, 3); err == nil {
+			t.Errorf("Expected an error for %q", label)
+		}
+	}
+}
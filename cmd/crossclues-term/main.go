@@ -0,0 +1,279 @@
+// Command crossclues-term plays CrossClues from a terminal.
+//
+//	crossclues-term -name Alice                 # create a room
+//	crossclues-term -name Bob -room BAKELU      # join one
+//	crossclues-term -name Carol -quick          # quick-join a public room
+//
+// Once in a room, type a cell label such as "B3" to mark your card there as
+// guessed, "x B3" to discard it, "ask WORD" to see where a clue points,
+// "start" to (re)start the game and "quit" to leave. The screen refreshes whenever the game changes.
+package main
+
+import (
+	"bufio"
+	"context"
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"os"
+	"os/signal"
+	"reflect"
+	"strings"
+	"syscall"
+	"time"
+
+	"github.com/dfturn/crossclues2/client"
+)
+
+const pollInterval = 2 * time.Second
+
+const helpText = `Commands:
+  B3          mark your card at B3 as guessed correctly
+  x B3        discard your card at B3
+  ask WORD    show which cells the computer guesser thinks WORD points at
+  start       start or restart the game
+  r           redraw now
+  quit        leave the room and exit (Ctrl-D exits without leaving)`
+
+func main() {
+	server := flag.String("server", envOr("CROSSCLUES_SERVER", "http://localhost:8080"), "server URL")
+	name := flag.String("name", os.Getenv("USER"), "player name")
+	room := flag.String("room", "", "room code to join; creates a room if empty")
+	quick := flag.Bool("quick", false, "quick-join an open public room")
+	gridSize := flag.Int("grid", 0, "grid size for a new room (default: server default)")
+	password := flag.String("password", "", "room password")
+	public := flag.Bool("public", false, "list a new room in the lobby")
+	flag.Parse()
+
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+	defer stop()
+
+	g := &game{api: client.New(*server), out: os.Stdout}
+	if err := g.enter(ctx, *name, *room, *quick, *gridSize, *password, *public); err != nil {
+		fmt.Fprintln(os.Stderr, "crossclues-term:", err)
+		os.Exit(1)
+	}
+	if err := g.play(ctx, os.Stdin); err != nil && !errors.Is(err, context.Canceled) {
+		fmt.Fprintln(os.Stderr, "crossclues-term:", err)
+		os.Exit(1)
+	}
+}
+
+func envOr(key, fallback string) string {
+	if v := os.Getenv(key); v != "" {
+		return v
+	}
+	return fallback
+}
+
+// game is one player's session in a room
+type game struct {
+	api      *client.Client
+	out      io.Writer
+	roomCode string
+	player   string
+	password string // for private rooms
+
+	state  *client.GameStateResponse
+	status string // result of the last command
+}
+
+// enter creates, joins or quick-joins a room
+func (g *game) enter(ctx context.Context, name, room string, quick bool, gridSize int, password string, public bool) error {
+	switch {
+	case quick:
+		resp, err := g.api.QuickJoin(ctx, client.QuickJoinRequest{PlayerName: name, GridSize: gridSize})
+		if err != nil {
+			return err
+		}
+		g.roomCode, g.player = resp.RoomCode, resp.PlayerName
+	case room != "":
+		resp, err := g.api.JoinRoom(ctx, room, client.JoinRoomRequest{PlayerName: name, Password: password})
+		if err != nil {
+			return err
+		}
+		g.roomCode, g.player, g.password = resp.RoomCode, resp.PlayerName, password
+	default:
+		resp, err := g.api.CreateRoom(ctx, client.CreateRoomRequest{
+			PlayerName: name,
+			GridSize:   gridSize,
+			Password:   password,
+			Public:     public,
+		})
+		if err != nil {
+			return err
+		}
+		g.roomCode, g.player, g.password = resp.RoomCode, resp.PlayerName, password
+		g.status = fmt.Sprintf("Created room %s; share the code so others can join.", g.roomCode)
+	}
+	return nil
+}
+
+// play runs the input and refresh loop until the player quits, input ends
+// or ctx is cancelled
+func (g *game) play(ctx context.Context, in io.Reader) error {
+	lines := make(chan string)
+	go func() {
+		defer close(lines)
+		scanner := bufio.NewScanner(in)
+		for scanner.Scan() {
+			lines <- scanner.Text()
+		}
+	}()
+
+	ticker := time.NewTicker(pollInterval)
+	defer ticker.Stop()
+
+	g.refresh(ctx, true)
+	for {
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case <-ticker.C:
+			g.refresh(ctx, false)
+		case line, ok := <-lines:
+			if !ok {
+				return nil
+			}
+			if quit := g.command(ctx, line); quit {
+				return nil
+			}
+			g.refresh(ctx, true)
+		}
+	}
+}
+
+// command runs one line of input and reports whether the player quit
+func (g *game) command(ctx context.Context, line string) bool {
+	fields := strings.Fields(strings.ToLower(line))
+	if len(fields) == 0 {
+		return false
+	}
+
+	switch fields[0] {
+	case "quit", "leave", "q":
+		if _, err := g.api.LeaveRoom(ctx, g.roomCode, g.player); err != nil {
+			fmt.Fprintln(g.out, "Could not leave the room:", err)
+		}
+		return true
+	case "help", "?":
+		g.status = helpText
+	case "start":
+		g.status = result(g.api.StartGame(ctx, g.roomCode))
+	case "ask":
+		if len(fields) != 2 {
+			g.status = "Usage: ask WORD"
+			break
+		}
+		g.status = g.ask(ctx, fields[1])
+	case "r", "refresh":
+		g.status = ""
+	case "x", "discard":
+		if len(fields) != 2 {
+			g.status = "Usage: x B3"
+			break
+		}
+		g.status = g.guess(ctx, fields[1], false)
+	case "ok", "guess":
+		if len(fields) != 2 {
+			g.status = "Usage: B3"
+			break
+		}
+		g.status = g.guess(ctx, fields[1], true)
+	default:
+		if len(fields) == 1 {
+			g.status = g.guess(ctx, fields[0], true)
+		} else {
+			g.status = fmt.Sprintf("Unknown command %q; type help for a list.", line)
+		}
+	}
+	return false
+}
+
+func (g *game) guess(ctx context.Context, label string, correct bool) string {
+	gridSize := 0
+	if g.state != nil {
+		gridSize = g.state.GridSize
+	}
+	card, err := parseLabel(label, gridSize)
+	if err != nil {
+		return err.Error()
+	}
+	resp, err := g.api.Guess(ctx, g.roomCode, client.GuessRequest{
+		PlayerName: g.player,
+		Row:        card.Row,
+		Column:     card.Column,
+		Correct:    correct,
+	})
+	if err != nil {
+		return err.Error()
+	}
+	verb := "Guessed"
+	if !correct {
+		verb = "Discarded"
+	}
+	if resp.GameOver {
+		return verb + " " + cardLabel(card) + ". That was the last card!"
+	}
+	return verb + " " + cardLabel(card) + "."
+}
+
+// maxAskMatches is how many cells ask lists
+const maxAskMatches = 3
+
+// ask shows the cells the server's guesser ranks highest for a clue, for
+// practising clues alone
+func (g *game) ask(ctx context.Context, clue string) string {
+	resp, err := g.api.MatchClue(ctx, g.roomCode, g.player, g.password, clue, false)
+	if err != nil {
+		return err.Error()
+	}
+	var picks []string
+	for _, m := range resp.Cells {
+		if len(picks) == maxAskMatches || m.Score == 0 {
+			break
+		}
+		picks = append(picks, fmt.Sprintf("%s (%s/%s, %.2f)", cardLabel(client.Card{Row: m.Row, Column: m.Column}), m.RowWord, m.ColumnWord, m.Score))
+	}
+	if len(picks) == 0 {
+		return fmt.Sprintf("The guesser has no idea what %q points at.", resp.Clue)
+	}
+	return fmt.Sprintf("For %q the guesser would pick %s.", resp.Clue, strings.Join(picks, ", then "))
+}
+
+func result[T any](_ T, err error) string {
+	if err != nil {
+		return err.Error()
+	}
+	return "OK."
+}
+
+// refresh fetches the game state and redraws the screen if it changed or
+// force is set
+func (g *game) refresh(ctx context.Context, force bool) {
+	state, err := g.api.State(ctx, g.roomCode, g.player)
+	if err != nil {
+		if ctx.Err() == nil {
+			g.status = "Could not refresh: " + err.Error()
+			g.draw()
+		}
+		return
+	}
+	if !force && reflect.DeepEqual(state, g.state) {
+		return
+	}
+	g.state = state
+	g.draw()
+}
+
+func (g *game) draw() {
+	fmt.Fprint(g.out, clearScreen)
+	if g.state != nil {
+		render(g.out, g.state, g.player)
+	}
+	if g.status != "" {
+		fmt.Fprintf(g.out, "\n%s\n", g.status)
+	}
+	fmt.Fprint(g.out, "\n> ")
+}
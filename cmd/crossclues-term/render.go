@@ -0,0 +1,73 @@
+package main
+
+import (
+	"fmt"
+	"io"
+	"strings"
+
+	"github.com/dfturn/crossclues2/client"
+	"github.com/dfturn/crossclues2/internal/board"
+)
+
+// Grid cell markers
+const (
+	markCorrect   = "✓"
+	markDiscarded = "✗"
+	markOpen      = "·"
+)
+
+var gridMarks = board.Marks{Guessed: markCorrect, Discarded: markDiscarded, Open: markOpen}
+
+const clearScreen = "\033[H\033[2J"
+
+// render draws the game as seen by the player: the grid with row letters and
+// column numbers, then the player's hand
+func render(w io.Writer, state *client.GameStateResponse, playerName string) {
+	fmt.Fprintf(w, "Room %s  ·  %s  ·  players: %s\n\n", state.RoomCode, playerName, strings.Join(state.Players, ", "))
+
+	if !state.GameStarted {
+		fmt.Fprintln(w, "Waiting for the game to start. Type \"start\" once everyone has joined.")
+		return
+	}
+
+	correct, total := state.CorrectGuesses, state.TotalCells
+	if state.GameOver {
+		fmt.Fprintf(w, "Game over: %d of %d cells guessed. Type \"start\" to play again.\n\n", correct, total)
+		if state.Share != "" {
+			fmt.Fprintf(w, "Share your result:\n%s\n\n", state.Share)
+		}
+	} else {
+		fmt.Fprintf(w, "%d of %d cells guessed\n\n", correct, total)
+	}
+
+	inHand := make(map[client.Card]bool, len(state.PlayerCards))
+	for _, card := range state.PlayerCards {
+		inHand[card] = true
+	}
+
+	grid := board.Lines(state.RowWords, state.ColumnWords, gridMarks, func(row, col int) board.Cell {
+		switch cell := state.Grid[row][col]; {
+		case cell.GuessedCorrectly:
+			return board.Guessed
+		case cell.DiscardedByMe:
+			return board.Discarded
+		case inHand[client.Card{Row: row, Column: col}]:
+			return board.Held
+		}
+		return board.Open
+	})
+	for _, line := range grid {
+		fmt.Fprintln(w, line)
+	}
+
+	fmt.Fprintln(w)
+	if len(state.PlayerCards) == 0 {
+		fmt.Fprintln(w, "Your hand is empty.")
+		return
+	}
+	labels := make([]string, len(state.PlayerCards))
+	for i, card := range state.PlayerCards {
+		labels[i] = fmt.Sprintf("%s (%s + %s)", cardLabel(card), state.RowWords[card.Row], state.ColumnWords[card.Column])
+	}
+	fmt.Fprintf(w, "Your cards: %s\n", strings.Join(labels, ", "))
+}
@@ -0,0 +1,47 @@
+package main
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/dfturn/crossclues2/client"
+)
+
+func TestRender(t *testing.T) {
+	state := &client.GameStateResponse{
+		RoomCode:       "BAKELU",
+		GridSize:       2,
+		GameStarted:    true,
+		CorrectGuesses: 1,
+		TotalCells:     4,
+		RowWords:       []string{"APPLE", "RIVER"},
+		ColumnWords:    []string{"STONE", "CLOUD"},
+		PlayerCards:    []client.Card{{Row: 1, Column: 1}},
+		Grid: [][]client.CellResponse{
+			{{GuessedCorrectly: true}, {DiscardedByMe: true}},
+			{{}, {}},
+		},
+		Players: []string{"Alice", "Bob"},
+	}
+
+	var out strings.Builder
+	render(&out, state, "Alice")
+	got := out.String()
+	for _, want := range []string{"Room BAKELU", "1 of 4", "A APPLE", "B RIVER", markCorrect, markDiscarded, "B2", "Your cards: B2 (RIVER + CLOUD)"} {
+		if !strings.Contains(got, want) {
+			t.Errorf("Expected output to contain %q:\n%s", want, got)
+		}
+	}
+	if strings.Contains(got, "Share") {
+		t.Errorf("Expected no summary while the game is running:\n%s", got)
+	}
+
+	state.GameOver = true
+	state.PlayerCards = nil
+	state.Share = "CrossClues 2026-10-18\n1/4 on a 2×2 grid"
+	out.Reset()
+	render(&out, state, "Alice")
+	if got := out.String(); !strings.Contains(got, "Share your result:\n"+state.Share) {
+		t.Errorf("Expected the summary once the game is over:\n%s", got)
+	}
+}
@@ -0,0 +1,305 @@
+package main
+
+import (
+	"encoding/json"
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"net/url"
+	"os"
+	"strconv"
+	"strings"
+
+	"github.com/dfturn/crossclues2/internal/board"
+)
+
+// Config holds the server's runtime configuration. Values are resolved in
+// order of increasing precedence: built-in defaults, the optional JSON config
+// file, environment variables, then command-line flags.
+type Config struct {
+	ListenAddr        string   `json:"listenAddr"`
+	LineAddr          string   `json:"lineAddr"` // Plain-text protocol listener; disabled if empty
+	StaticDir         string   `json:"staticDir"`
+	PublicURL         string   `json:"publicURL"` // Where players reach the server, for shared links; taken from each request if empty
+	CORSOrigins       []string `json:"corsOrigins"`
+	DefaultGridSize   int      `json:"defaultGridSize"`
+	MinGridSize       int      `json:"minGridSize"`
+	MaxGridSize       int      `json:"maxGridSize"`
+	MaxRooms          int      `json:"maxRooms"`
+	MaxPlayersPerRoom int      `json:"maxPlayersPerRoom"`
+	WordPackDir       string   `json:"wordPackDir"`
+	AssociationsFile  string   `json:"associationsFile"`
+	AdminSecret       string   `json:"adminSecret"`
+	LogFormat         string   `json:"logFormat"`
+
+	TrustedProxyHeader string          `json:"trustedProxyHeader"`
+	RateLimits         RateLimitConfig `json:"rateLimits"`
+
+	// warnings are problems found while loading that don't stop the server,
+	// logged once logging is set up
+	warnings []string
+}
+
+// config is the active configuration. It is set once at startup before the
+// server begins handling requests.
+var config = defaultConfig()
+
+func defaultConfig() Config {
+	return Config{
+		ListenAddr:        ":8080",
+		StaticDir:         "static",
+		CORSOrigins:       []string{"*"},
+		DefaultGridSize:   DefaultGridSize,
+		MinGridSize:       MinGridSize,
+		MaxGridSize:       MaxGridSize,
+		MaxRooms:          0,
+		MaxPlayersPerRoom: 12,
+		LogFormat:         "json",
+		RateLimits: RateLimitConfig{
+			CreatePerMinute:     10,
+			JoinPerMinute:       30,
+			ActionPerMinute:     120,
+			RoomJoinPerMinute:   30,
+			RoomActionPerMinute: 240,
+
+			PasswordFailuresPerMinute: 5,
+		},
+	}
+}
+
+// Validate checks that the configuration is internally consistent
+func (c Config) Validate() error {
+	var errs []error
+	if c.ListenAddr == "" {
+		errs = append(errs, errors.New("listen address is required"))
+	}
+	if c.MinGridSize < 1 {
+		errs = append(errs, fmt.Errorf("min grid size must be at least 1, got %d", c.MinGridSize))
+	}
+	if c.MaxGridSize < c.MinGridSize {
+		errs = append(errs, fmt.Errorf("max grid size %d is less than min grid size %d", c.MaxGridSize, c.MinGridSize))
+	}
+	if c.DefaultGridSize < c.MinGridSize || c.DefaultGridSize > c.MaxGridSize {
+		errs = append(errs, fmt.Errorf("default grid size %d is outside %d-%d", c.DefaultGridSize, c.MinGridSize, c.MaxGridSize))
+	}
+	if c.MaxGridSize > board.MaxSize {
+		errs = append(errs, fmt.Errorf("max grid size %d is above %d, the most rows that can be labelled A-Z", c.MaxGridSize, board.MaxSize))
+	}
+	if 2*c.MaxGridSize > len(wordList) {
+		errs = append(errs, fmt.Errorf("max grid size %d needs %d words, built-in list has %d", c.MaxGridSize, 2*c.MaxGridSize, len(wordList)))
+	}
+	if c.MaxRooms < 0 {
+		errs = append(errs, fmt.Errorf("max rooms must not be negative, got %d", c.MaxRooms))
+	}
+	if c.MaxPlayersPerRoom < 0 || c.MaxPlayersPerRoom == 1 {
+		errs = append(errs, fmt.Errorf("max players per room must be 0 (unlimited) or at least 2, got %d", c.MaxPlayersPerRoom))
+	}
+	for name, v := range map[string]int{
+		"create":      c.RateLimits.CreatePerMinute,
+		"join":        c.RateLimits.JoinPerMinute,
+		"action":      c.RateLimits.ActionPerMinute,
+		"room join":   c.RateLimits.RoomJoinPerMinute,
+		"room action": c.RateLimits.RoomActionPerMinute,
+		"password":    c.RateLimits.PasswordFailuresPerMinute,
+	} {
+		if v < 0 {
+			errs = append(errs, fmt.Errorf("%s rate limit must not be negative, got %d", name, v))
+		}
+	}
+	if c.PublicURL != "" {
+		if u, err := url.Parse(c.PublicURL); err != nil || (u.Scheme != "http" && u.Scheme != "https") || u.Host == "" {
+			errs = append(errs, fmt.Errorf("public URL must be an absolute http or https URL, got %q", c.PublicURL))
+		}
+	}
+	if c.LogFormat != "json" && c.LogFormat != "text" {
+		errs = append(errs, fmt.Errorf("log format must be json or text, got %q", c.LogFormat))
+	}
+	return errors.Join(errs...)
+}
+
+// Redacted returns a copy of the configuration that is safe to log
+func (c Config) Redacted() Config {
+	if c.AdminSecret != "" {
+		c.AdminSecret = "<redacted>"
+	}
+	return c
+}
+
+// WriteTo writes the configuration as indented JSON
+func (c Config) WriteTo(w io.Writer) (int64, error) {
+	data, err := json.MarshalIndent(c, "", "  ")
+	if err != nil {
+		return 0, err
+	}
+	n, err := w.Write(append(data, '\n'))
+	return int64(n), err
+}
+
+// loadConfig resolves the configuration from defaults, config file,
+// environment and flags. printConfig reports whether --print-config was given.
+func loadConfig(args []string, getenv func(string) string) (cfg Config, printConfig bool, err error) {
+	cfg = defaultConfig()
+
+	fs := flag.NewFlagSet("crossclues2", flag.ContinueOnError)
+	configFile := fs.String("config", "", "path to a JSON config file (env CROSSCLUES_CONFIG)")
+	fs.BoolVar(&printConfig, "print-config", false, "print the effective configuration and exit")
+	addr := fs.String("addr", cfg.ListenAddr, "listen address (env CROSSCLUES_ADDR, or PORT)")
+	lineAddr := fs.String("line-addr", cfg.LineAddr, "listen address for the plain-text line protocol, empty to disable (env CROSSCLUES_LINE_ADDR)")
+	staticDir := fs.String("static-dir", cfg.StaticDir, "directory of frontend files to serve (env CROSSCLUES_STATIC_DIR)")
+	publicURL := fs.String("public-url", cfg.PublicURL, "URL players reach the server at, for links in shared results (env CROSSCLUES_PUBLIC_URL)")
+	corsOrigins := fs.String("cors-origins", strings.Join(cfg.CORSOrigins, ","), "comma-separated allowed CORS origins, or * (env CROSSCLUES_CORS_ORIGINS)")
+	defaultGrid := fs.Int("default-grid-size", cfg.DefaultGridSize, "grid size used when a room doesn't specify one (env CROSSCLUES_DEFAULT_GRID_SIZE)")
+	minGrid := fs.Int("min-grid-size", cfg.MinGridSize, "smallest allowed grid size (env CROSSCLUES_MIN_GRID_SIZE)")
+	maxGrid := fs.Int("max-grid-size", cfg.MaxGridSize, "largest allowed grid size (env CROSSCLUES_MAX_GRID_SIZE)")
+	maxRooms := fs.Int("max-rooms", cfg.MaxRooms, "maximum number of rooms, 0 for unlimited (env CROSSCLUES_MAX_ROOMS)")
+	maxPlayers := fs.Int("max-players", cfg.MaxPlayersPerRoom, "maximum players per room, 0 for unlimited (env CROSSCLUES_MAX_PLAYERS)")
+	wordPackDir := fs.String("word-pack-dir", cfg.WordPackDir, "directory of additional .txt word packs; see -associations-file for their clues (env CROSSCLUES_WORD_PACK_DIR)")
+	associationsFile := fs.String("associations-file", cfg.AssociationsFile, "file of extra word associations for clue suggestions (env CROSSCLUES_ASSOCIATIONS_FILE)")
+	logFormat := fs.String("log-format", cfg.LogFormat, "log output format, json or text (env CROSSCLUES_LOG_FORMAT)")
+	proxyHeader := fs.String("trusted-proxy-header", cfg.TrustedProxyHeader, "header carrying the client IP from a trusted proxy, e.g. X-Forwarded-For (env CROSSCLUES_TRUSTED_PROXY_HEADER)")
+	rateCreate := fs.Int("rate-create", cfg.RateLimits.CreatePerMinute, "rooms a client may create per minute, 0 for unlimited (env CROSSCLUES_RATE_CREATE)")
+	rateJoin := fs.Int("rate-join", cfg.RateLimits.JoinPerMinute, "joins a client may make per minute, 0 for unlimited (env CROSSCLUES_RATE_JOIN)")
+	rateAction := fs.Int("rate-action", cfg.RateLimits.ActionPerMinute, "game actions a client may make per minute, 0 for unlimited (env CROSSCLUES_RATE_ACTION)")
+	rateRoomJoin := fs.Int("rate-room-join", cfg.RateLimits.RoomJoinPerMinute, "joins a room accepts per minute, 0 for unlimited (env CROSSCLUES_RATE_ROOM_JOIN)")
+	rateRoomAction := fs.Int("rate-room-action", cfg.RateLimits.RoomActionPerMinute, "game actions a room accepts per minute, 0 for unlimited (env CROSSCLUES_RATE_ROOM_ACTION)")
+	ratePassword := fs.Int("rate-password-failures", cfg.RateLimits.PasswordFailuresPerMinute, "wrong room passwords a client may try per room per minute, 0 for unlimited (env CROSSCLUES_RATE_PASSWORD_FAILURES)")
+
+	if err := fs.Parse(args); err != nil {
+		return cfg, false, err
+	}
+
+	// Config file
+	path := *configFile
+	if path == "" {
+		path = getenv("CROSSCLUES_CONFIG")
+	}
+	if path != "" {
+		f, err := os.Open(path)
+		if err != nil {
+			return cfg, false, fmt.Errorf("reading config file: %w", err)
+		}
+		// Unknown keys are usually typos that would otherwise be ignored
+		dec := json.NewDecoder(f)
+		dec.DisallowUnknownFields()
+		err = dec.Decode(&cfg)
+		if err == nil {
+			if _, extra := dec.Token(); extra != io.EOF {
+				err = errors.New("unexpected data after the config object")
+			}
+		}
+		f.Close()
+		if err != nil {
+			return cfg, false, fmt.Errorf("parsing config file %s: %w", path, err)
+		}
+	}
+
+	// Environment
+	if port := getenv("PORT"); port != "" {
+		cfg.ListenAddr = ":" + port
+	}
+	envString := func(name string, dst *string) {
+		if v := getenv(name); v != "" {
+			*dst = v
+		}
+	}
+	envInt := func(name string, dst *int) {
+		if v := getenv(name); v != "" {
+			n, convErr := strconv.Atoi(v)
+			if convErr != nil {
+				err = errors.Join(err, fmt.Errorf("%s: %q is not an integer", name, v))
+				return
+			}
+			*dst = n
+		}
+	}
+	envString("CROSSCLUES_ADDR", &cfg.ListenAddr)
+	envString("CROSSCLUES_LINE_ADDR", &cfg.LineAddr)
+	envString("CROSSCLUES_STATIC_DIR", &cfg.StaticDir)
+	envString("CROSSCLUES_PUBLIC_URL", &cfg.PublicURL)
+	if v := getenv("CROSSCLUES_CORS_ORIGINS"); v != "" {
+		cfg.CORSOrigins = splitList(v)
+	}
+	envInt("CROSSCLUES_DEFAULT_GRID_SIZE", &cfg.DefaultGridSize)
+	envInt("CROSSCLUES_MIN_GRID_SIZE", &cfg.MinGridSize)
+	envInt("CROSSCLUES_MAX_GRID_SIZE", &cfg.MaxGridSize)
+	envInt("CROSSCLUES_MAX_ROOMS", &cfg.MaxRooms)
+	envInt("CROSSCLUES_MAX_PLAYERS", &cfg.MaxPlayersPerRoom)
+	envString("CROSSCLUES_WORD_PACK_DIR", &cfg.WordPackDir)
+	envString("CROSSCLUES_ASSOCIATIONS_FILE", &cfg.AssociationsFile)
+	envString("CROSSCLUES_ADMIN_SECRET", &cfg.AdminSecret)
+	if v := getenv("ADMIN_SECRET"); v != "" && getenv("CROSSCLUES_ADMIN_SECRET") == "" {
+		// The admin API first read its secret from ADMIN_SECRET
+		cfg.AdminSecret = v
+		cfg.warnings = append(cfg.warnings, "ADMIN_SECRET is deprecated, set CROSSCLUES_ADMIN_SECRET instead")
+	}
+	envString("CROSSCLUES_LOG_FORMAT", &cfg.LogFormat)
+	envString("CROSSCLUES_TRUSTED_PROXY_HEADER", &cfg.TrustedProxyHeader)
+	envInt("CROSSCLUES_RATE_CREATE", &cfg.RateLimits.CreatePerMinute)
+	envInt("CROSSCLUES_RATE_JOIN", &cfg.RateLimits.JoinPerMinute)
+	envInt("CROSSCLUES_RATE_ACTION", &cfg.RateLimits.ActionPerMinute)
+	envInt("CROSSCLUES_RATE_ROOM_JOIN", &cfg.RateLimits.RoomJoinPerMinute)
+	envInt("CROSSCLUES_RATE_ROOM_ACTION", &cfg.RateLimits.RoomActionPerMinute)
+	envInt("CROSSCLUES_RATE_PASSWORD_FAILURES", &cfg.RateLimits.PasswordFailuresPerMinute)
+	if err != nil {
+		return cfg, false, err
+	}
+
+	// Flags explicitly given on the command line win
+	fs.Visit(func(f *flag.Flag) {
+		switch f.Name {
+		case "addr":
+			cfg.ListenAddr = *addr
+		case "line-addr":
+			cfg.LineAddr = *lineAddr
+		case "static-dir":
+			cfg.StaticDir = *staticDir
+		case "public-url":
+			cfg.PublicURL = *publicURL
+		case "cors-origins":
+			cfg.CORSOrigins = splitList(*corsOrigins)
+		case "default-grid-size":
+			cfg.DefaultGridSize = *defaultGrid
+		case "min-grid-size":
+			cfg.MinGridSize = *minGrid
+		case "max-grid-size":
+			cfg.MaxGridSize = *maxGrid
+		case "max-rooms":
+			cfg.MaxRooms = *maxRooms
+		case "max-players":
+			cfg.MaxPlayersPerRoom = *maxPlayers
+		case "word-pack-dir":
+			cfg.WordPackDir = *wordPackDir
+		case "associations-file":
+			cfg.AssociationsFile = *associationsFile
+		case "log-format":
+			cfg.LogFormat = *logFormat
+		case "trusted-proxy-header":
+			cfg.TrustedProxyHeader = *proxyHeader
+		case "rate-create":
+			cfg.RateLimits.CreatePerMinute = *rateCreate
+		case "rate-join":
+			cfg.RateLimits.JoinPerMinute = *rateJoin
+		case "rate-action":
+			cfg.RateLimits.ActionPerMinute = *rateAction
+		case "rate-room-join":
+			cfg.RateLimits.RoomJoinPerMinute = *rateRoomJoin
+		case "rate-room-action":
+			cfg.RateLimits.RoomActionPerMinute = *rateRoomAction
+		case "rate-password-failures":
+			cfg.RateLimits.PasswordFailuresPerMinute = *ratePassword
+		}
+	})
+
+	return cfg, printConfig, cfg.Validate()
+}
+
+func splitList(s string) []string {
+	var out []string
+	for _, part := range strings.Split(s, ",") {
+		if part = strings.TrimSpace(part); part != "" {
+			out = append(out, part)
+		}
+	}
+	return out
+}
@@ -0,0 +1,125 @@
+package main
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func envMap(m map[string]string) func(string) string {
+	return func(key string) string { return m[key] }
+}
+
+func TestLoadConfigDefaults(t *testing.T) {
+	cfg, printConfig, err := loadConfig(nil, envMap(nil))
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	if printConfig {
+		t.Error("Expected printConfig to be false")
+	}
+	if cfg.ListenAddr != ":8080" {
+		t.Errorf("Expected listen address :8080, got %s", cfg.ListenAddr)
+	}
+	if cfg.DefaultGridSize != DefaultGridSize || cfg.MinGridSize != MinGridSize || cfg.MaxGridSize != MaxGridSize {
+		t.Errorf("Expected default grid sizes, got %d/%d/%d", cfg.DefaultGridSize, cfg.MinGridSize, cfg.MaxGridSize)
+	}
+}
+
+func TestLoadConfigPrecedence(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "config.json")
+	os.WriteFile(path, []byte(`{"listenAddr": ":7000", "maxRooms": 10, "maxPlayersPerRoom": 6}`), 0o644)
+
+	env := envMap(map[string]string{
+		"CROSSCLUES_CONFIG":    path,
+		"PORT":                 "9000",
+		"CROSSCLUES_MAX_ROOMS": "20",
+	})
+	cfg, _, err := loadConfig([]string{"-max-rooms", "30"}, env)
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+
+	// Environment overrides the file
+	if cfg.ListenAddr != ":9000" {
+		t.Errorf("Expected listen address :9000 from PORT, got %s", cfg.ListenAddr)
+	}
+	// Flags override the environment
+	if cfg.MaxRooms != 30 {
+		t.Errorf("Expected max rooms 30 from flag, got %d", cfg.MaxRooms)
+	}
+	// File values survive when nothing overrides them
+	if cfg.MaxPlayersPerRoom != 6 {
+		t.Errorf("Expected max players 6 from file, got %d", cfg.MaxPlayersPerRoom)
+	}
+}
+
+func TestLoadConfigValidation(t *testing.T) {
+	tests := []struct {
+		name string
+		args []string
+		env  map[string]string
+	}{
+		{"min above max", []string{"-min-grid-size", "6", "-max-grid-size", "5"}, nil},
+		{"default outside range", []string{"-default-grid-size", "2"}, nil},
+		{"negative room cap", []string{"-max-rooms", "-1"}, nil},
+		{"single player cap", []string{"-max-players", "1"}, nil},
+		{"relative public URL", []string{"-public-url", "crossclues.example"}, nil},
+		{"non-http public URL", nil, map[string]string{"CROSSCLUES_PUBLIC_URL": "ftp://crossclues.example"}},
+		{"bad env integer", nil, map[string]string{"CROSSCLUES_MAX_ROOMS": "lots"}},
+		{"more rows than letters", []string{"-max-grid-size", "27"}, nil},
+	}
+
+	for _, tc := range tests {
+		if _, _, err := loadConfig(tc.args, envMap(tc.env)); err == nil {
+			t.Errorf("%s: expected a validation error", tc.name)
+		}
+	}
+}
+
+func TestLoadConfigRejectsBadFile(t *testing.T) {
+	for name, data := range map[string]string{
+		"unknown key":   `{"maxRoom": 10}`,
+		"trailing data": `{"maxRooms": 10} {"maxRooms": 20}`,
+	} {
+		path := filepath.Join(t.TempDir(), "config.json")
+		os.WriteFile(path, []byte(data), 0o644)
+		if _, _, err := loadConfig(nil, envMap(map[string]string{"CROSSCLUES_CONFIG": path})); err == nil {
+			t.Errorf("%s: expected a parse error", name)
+		}
+	}
+}
+
+func TestLoadConfigDeprecatedAdminSecret(t *testing.T) {
+	cfg, _, err := loadConfig(nil, envMap(map[string]string{"ADMIN_SECRET": "old"}))
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	if cfg.AdminSecret != "old" || len(cfg.warnings) != 1 {
+		t.Errorf("Expected the old variable to be used with a warning, got %q and %v", cfg.AdminSecret, cfg.warnings)
+	}
+
+	// The new name wins when both are set
+	cfg, _, _ = loadConfig(nil, envMap(map[string]string{"ADMIN_SECRET": "old", "CROSSCLUES_ADMIN_SECRET": "new"}))
+	if cfg.AdminSecret != "new" || len(cfg.warnings) != 0 {
+		t.Errorf("Expected CROSSCLUES_ADMIN_SECRET without a warning, got %q and %v", cfg.AdminSecret, cfg.warnings)
+	}
+}
+
+func TestLoadWordPacks(t *testing.T) {
+	dir := t.TempDir()
+	os.WriteFile(filepath.Join(dir, "animals.txt"), []byte("# Animals\ncat\ndog\n\nCAT\nowl\nfox\nbee\nemu\n"), 0o644)
+	defer delete(wordPacks, "animals")
+
+	if err := loadWordPacks(dir, 6); err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	words := wordPacks["animals"]
+	if len(words) != 6 || words[0] != "CAT" {
+		t.Errorf("Expected 6 upper-cased distinct words, got %v", words)
+	}
+
+	if err := loadWordPacks(dir, 10); err == nil {
+		t.Error("Expected an error for a pack with too few words")
+	}
+}
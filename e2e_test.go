@@ -0,0 +1,478 @@
+package main
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"reflect"
+	"sort"
+	"strings"
+	"sync"
+	"testing"
+)
+
+// End-to-end tests: full games played over HTTP against a real server, as
+// the frontend plays them. Run with -race to check concurrent clients.
+
+// e2eClient sends raw JSON requests and decodes responses loosely, so the
+// tests see exactly what goes over the wire
+type e2eClient struct {
+	t    testing.TB
+	base string
+}
+
+func newE2EServer(t *testing.T) *e2eClient {
+	t.Helper()
+	ClearRooms()
+	srv := httptest.NewServer(newRouter())
+	t.Cleanup(srv.Close)
+	return &e2eClient{t: t, base: srv.URL}
+}
+
+// e2eResponse is a response with its JSON body decoded into a map
+type e2eResponse struct {
+	*http.Response
+	Body map[string]any
+}
+
+func (c *e2eClient) do(method, path string, body any, header ...string) *e2eResponse {
+	c.t.Helper()
+	var r io.Reader
+	switch b := body.(type) {
+	case nil:
+	case string:
+		r = strings.NewReader(b)
+	default:
+		data, _ := json.Marshal(b)
+		r = bytes.NewReader(data)
+	}
+	req, err := http.NewRequest(method, c.base+path, r)
+	if err != nil {
+		c.t.Fatal(err)
+	}
+	for i := 0; i+1 < len(header); i += 2 {
+		req.Header.Set(header[i], header[i+1])
+	}
+	resp, err := http.DefaultClient.Do(req)
+	if err != nil {
+		c.t.Fatalf("%s %s: %v", method, path, err)
+	}
+	defer resp.Body.Close()
+
+	out := &e2eResponse{Response: resp}
+	data, _ := io.ReadAll(resp.Body)
+	if len(data) > 0 && resp.Header.Get("Content-Type") == "application/json" {
+		if err := json.Unmarshal(data, &out.Body); err != nil {
+			c.t.Fatalf("%s %s: invalid JSON %q: %v", method, path, data, err)
+		}
+	}
+	return out
+}
+
+// expect checks the status of a successful response and that its body has
+// exactly the given fields
+func (c *e2eClient) expect(resp *e2eResponse, status int, fields ...string) {
+	c.t.Helper()
+	if resp.StatusCode != status {
+		c.t.Fatalf("%s %s: expected %d, got %d: %v", resp.Request.Method, resp.Request.URL.Path, status, resp.StatusCode, resp.Body)
+	}
+	if ct := resp.Header.Get("Content-Type"); ct != "application/json" {
+		c.t.Errorf("%s %s: expected a JSON response, got %q", resp.Request.Method, resp.Request.URL.Path, ct)
+	}
+	if got := keys(resp.Body); !reflect.DeepEqual(got, sorted(fields)) {
+		c.t.Errorf("%s %s: expected fields %v, got %v", resp.Request.Method, resp.Request.URL.Path, sorted(fields), got)
+	}
+}
+
+// expectError checks that a response is an error body with the given status
+// and code, tied to the response's request ID
+func (c *e2eClient) expectError(resp *e2eResponse, status int, code string) {
+	c.t.Helper()
+	where := resp.Request.Method + " " + resp.Request.URL.String()
+	if resp.StatusCode != status || resp.Body["code"] != code {
+		c.t.Errorf("%s: expected %d %s, got %d %v", where, status, code, resp.StatusCode, resp.Body["code"])
+		return
+	}
+	if msg, _ := resp.Body["error"].(string); msg == "" {
+		c.t.Errorf("%s: expected an error message, got %v", where, resp.Body)
+	}
+	if id := resp.Header.Get(requestIDHeader); id == "" || resp.Body["requestId"] != id {
+		c.t.Errorf("%s: expected requestId %q in the body, got %v", where, id, resp.Body["requestId"])
+	}
+	for field := range resp.Body {
+		switch field {
+		case "error", "code", "details", "requestId":
+		default:
+			c.t.Errorf("%s: unexpected field %q in error body", where, field)
+		}
+	}
+}
+
+// state fetches a player's view of the game and decodes it
+func (c *e2eClient) state(room, player string) GameStateResponse {
+	c.t.Helper()
+	resp := c.do(http.MethodGet, "/api/v1/rooms/"+room+"/state?playerName="+url.QueryEscape(player), nil)
+	fields := []string{"roomCode", "gridSize", "gameStarted", "gameOver", "correctGuesses",
+		"totalCells", "rowWords", "columnWords", "playerCards", "grid", "players"}
+	if resp.Body["gameOver"] == true {
+		fields = append(fields, "share")
+	}
+	c.expect(resp, http.StatusOK, fields...)
+	var state GameStateResponse
+	data, _ := json.Marshal(resp.Body)
+	json.Unmarshal(data, &state)
+	return state
+}
+
+func keys(m map[string]any) []string {
+	out := []string{}
+	for k := range m {
+		out = append(out, k)
+	}
+	return sorted(out)
+}
+
+func sorted(s []string) []string {
+	out := append([]string{}, s...)
+	sort.Strings(out)
+	return out
+}
+
+func TestE2EFullGame(t *testing.T) {
+	c := newE2EServer(t)
+
+	resp := c.do(http.MethodPost, "/api/v1/rooms", CreateRoomRequest{PlayerName: "Alice", GridSize: 3})
+	c.expect(resp, http.StatusCreated, "roomCode", "playerName", "message")
+	code := resp.Body["roomCode"].(string)
+
+	for _, name := range []string{"Bob", "Carol"} {
+		resp = c.do(http.MethodPost, "/api/v1/rooms/"+code+"/join", JoinRoomRequest{PlayerName: name})
+		c.expect(resp, http.StatusOK, "roomCode", "playerName", "cardsDealt", "message")
+		if resp.Body["cardsDealt"] != 2.0 {
+			t.Errorf("Expected 2 cards dealt to %s, got %v", name, resp.Body["cardsDealt"])
+		}
+	}
+
+	state := c.state(code, "Alice")
+	if state.GameStarted || len(state.Players) != 3 || state.TotalCells != 9 {
+		t.Errorf("Unexpected state before start: %+v", state)
+	}
+
+	resp = c.do(http.MethodPost, "/api/v1/rooms/"+code+"/start", nil)
+	c.expect(resp, http.StatusOK, "roomCode", "message")
+
+	// Everyone plays their cards until the board is resolved: the first card
+	// of each hand is guessed, the rest discarded
+	players := []string{"Alice", "Bob", "Carol"}
+	var discarded []Card
+	for moves := 0; ; moves++ {
+		if moves > 9 {
+			t.Fatal("Game did not finish in 9 moves")
+		}
+		state = c.state(code, players[moves%3])
+		if state.GameOver {
+			break
+		}
+		if len(state.PlayerCards) == 0 {
+			continue
+		}
+		card := state.PlayerCards[0]
+		correct := len(state.PlayerCards) == 1
+		resp = c.do(http.MethodPost, "/api/v1/rooms/"+code+"/guess", GuessRequest{
+			PlayerName: players[moves%3], Row: card.Row, Column: card.Column, Correct: correct,
+		})
+		c.expect(resp, http.StatusOK, "roomCode", "message", "gameOver")
+		if !correct {
+			discarded = append(discarded, card)
+		}
+	}
+
+	// Only the discarding player sees their discards; nobody holds cards
+	total := 0
+	for _, name := range players {
+		state = c.state(code, name)
+		if !state.GameOver || len(state.PlayerCards) != 0 {
+			t.Errorf("%s: expected a finished game with no cards, got %+v", name, state)
+		}
+		for _, card := range discarded {
+			if state.Grid[card.Row][card.Column].DiscardedByMe {
+				total++
+			}
+		}
+	}
+	if total != len(discarded) {
+		t.Errorf("Expected each discard to be visible to one player, got %d of %d", total, len(discarded))
+	}
+	if state.CorrectGuesses+len(discarded) != state.TotalCells {
+		t.Errorf("Expected %d guessed and %d discarded to cover %d cells", state.CorrectGuesses, len(discarded), state.TotalCells)
+	}
+
+	// The result can be shared, linking back to the server it was played on
+	resp = c.do(http.MethodGet, "/api/v1/rooms/"+code+"/share?playerName=carol", nil)
+	c.expect(resp, http.StatusOK, "roomCode", "game", "text", "link")
+	if link := c.base + "/?room=" + code; resp.Body["link"] != link || resp.Body["text"] != state.Share {
+		t.Errorf("Expected a summary linking to %s matching the state, got %v", link, resp.Body)
+	}
+
+	resp = c.do(http.MethodPost, "/api/v1/rooms/"+code+"/leave", JoinRoomRequest{PlayerName: "carol"})
+	c.expect(resp, http.StatusOK, "roomCode", "message")
+	if state = c.state(code, "Alice"); len(state.Players) != 2 {
+		t.Errorf("Expected 2 players after Carol left, got %v", state.Players)
+	}
+}
+
+func TestE2EErrors(t *testing.T) {
+	c := newE2EServer(t)
+	CreateRoomWithOptions("OPEN", "Alice", RoomOptions{GridSize: 3})
+	CreateRoomWithOptions("FULL", "Alice", RoomOptions{GridSize: 3, MaxPlayers: 2})
+	JoinRoom("FULL", "Bob")
+	CreateRoomWithOptions("LOCKED", "Alice", RoomOptions{GridSize: 3, Password: "secret"})
+	CreateRoomWithOptions("PLAYING", "Alice", RoomOptions{GridSize: 3})
+	JoinRoom("PLAYING", "Bob")
+	StartGame("PLAYING")
+	CreateRoomWithOptions("OVER", "Alice", RoomOptions{GridSize: 3})
+	JoinRoom("OVER", "Bob")
+	StartGame("OVER")
+	EndGame("OVER")
+
+	// A cell in the grid that Alice does not hold
+	room, _ := getRoom("PLAYING")
+	notHeld := room.PlayerHands["Bob"][0]
+
+	tests := []struct {
+		method, path string
+		body         any
+		status       int
+		code         string
+	}{
+		{"POST", "/rooms", `{`, 400, CodeInvalidRequest},
+		{"POST", "/rooms", CreateRoomRequest{}, 400, CodePlayerNameRequired},
+		{"POST", "/rooms", CreateRoomRequest{PlayerName: "Alice", GridSize: 9}, 400, CodeInvalidGridSize},
+		{"POST", "/rooms", CreateRoomRequest{PlayerName: strings.Repeat("A", 100)}, 400, CodePlayerNameTooLong},
+		{"POST", "/rooms", CreateRoomRequest{PlayerName: "Alice", RoomCode: "OPEN"}, 409, CodeRoomExists},
+		{"POST", "/rooms", CreateRoomRequest{PlayerName: "Alice", RoomCode: "BAD CODE!"}, 400, CodeInvalidRoomCode},
+		{"POST", "/rooms", CreateRoomRequest{PlayerName: "Alice", WordPack: "nope"}, 400, CodeUnknownWordPack},
+
+		{"POST", "/rooms/NOPE/join", JoinRoomRequest{PlayerName: "Bob"}, 404, CodeRoomNotFound},
+		{"POST", "/rooms/OPEN/join", `not json`, 400, CodeInvalidRequest},
+		{"POST", "/rooms/OPEN/join", JoinRoomRequest{PlayerName: "  "}, 400, CodePlayerNameRequired},
+		{"POST", "/rooms/OPEN/join", JoinRoomRequest{PlayerName: "ALICE"}, 409, CodePlayerExists},
+		{"POST", "/rooms/FULL/join", JoinRoomRequest{PlayerName: "Carol"}, 409, CodeRoomFull},
+		{"POST", "/rooms/LOCKED/join", JoinRoomRequest{PlayerName: "Bob", Password: "guess"}, 403, CodeWrongPassword},
+
+		{"POST", "/rooms/NOPE/leave", JoinRoomRequest{PlayerName: "Bob"}, 404, CodeRoomNotFound},
+		{"POST", "/rooms/OPEN/leave", JoinRoomRequest{PlayerName: "Zed"}, 404, CodePlayerNotFound},
+		{"POST", "/rooms/OPEN/leave", JoinRoomRequest{}, 400, CodePlayerNameRequired},
+
+		{"POST", "/rooms/NOPE/start", nil, 404, CodeRoomNotFound},
+		{"POST", "/rooms/OPEN/start", nil, 400, CodeNotEnoughPlayers},
+
+		{"POST", "/rooms/NOPE/guess", GuessRequest{PlayerName: "Alice"}, 404, CodeRoomNotFound},
+		{"POST", "/rooms/OPEN/guess", GuessRequest{PlayerName: "Alice"}, 400, CodeGameNotStarted},
+		{"POST", "/rooms/PLAYING/guess", `{"row": "A1"}`, 400, CodeInvalidRequest},
+		{"POST", "/rooms/PLAYING/guess", GuessRequest{PlayerName: "Alice", Row: -1}, 400, CodeInvalidCell},
+		{"POST", "/rooms/PLAYING/guess", GuessRequest{PlayerName: "Alice", Column: 99}, 400, CodeInvalidCell},
+		{"POST", "/rooms/PLAYING/guess", GuessRequest{PlayerName: "Alice", Row: 4, Column: 4}, 400, CodeNoCard},
+		{"POST", "/rooms/PLAYING/guess", GuessRequest{PlayerName: "Alice", Row: notHeld.Row, Column: notHeld.Column}, 400, CodeNoCard},
+		{"POST", "/rooms/PLAYING/guess", GuessRequest{PlayerName: "Zed"}, 404, CodePlayerNotFound},
+		{"POST", "/rooms/OVER/guess", GuessRequest{PlayerName: "Alice"}, 400, CodeGameOver},
+
+		{"GET", "/rooms/NOPE/state?playerName=Alice", nil, 404, CodeRoomNotFound},
+		{"GET", "/rooms/OPEN/state", nil, 400, CodePlayerNameRequired},
+		{"GET", "/rooms/OPEN/state?playerName=Zed", nil, 404, CodePlayerNotFound},
+
+		{"GET", "/rooms/NOPE/share?playerName=Alice", nil, 404, CodeRoomNotFound},
+		{"GET", "/rooms/PLAYING/share", nil, 400, CodePlayerNameRequired},
+		{"GET", "/rooms/PLAYING/share?playerName=Zed", nil, 404, CodePlayerNotFound},
+		{"GET", "/rooms/PLAYING/share?playerName=Alice", nil, 404, CodeNoFinishedGame},
+		{"GET", "/rooms/LOCKED/share?playerName=Alice&password=guess", nil, 403, CodeWrongPassword},
+
+		{"GET", "/rooms/OPEN/join", nil, 405, CodeMethodNotAllowed},
+		{"DELETE", "/rooms/OPEN", nil, 404, CodeNotFound},
+		{"POST", "/rooms/OPEN/dance", nil, 404, CodeNotFound},
+	}
+	for _, tt := range tests {
+		for _, prefix := range apiPrefixes {
+			c.expectError(c.do(tt.method, prefix+tt.path, tt.body), tt.status, tt.code)
+		}
+	}
+
+	// Grid size errors tell the client the allowed range
+	resp := c.do(http.MethodPost, "/api/v1/rooms", CreateRoomRequest{PlayerName: "Alice", GridSize: 2})
+	want := map[string]any{"min": float64(config.MinGridSize), "max": float64(config.MaxGridSize)}
+	if !reflect.DeepEqual(resp.Body["details"], want) {
+		t.Errorf("Expected details %v, got %v", want, resp.Body["details"])
+	}
+
+	// None of the failures changed the rooms they targeted
+	if state := c.state("PLAYING", "Alice"); state.CorrectGuesses != 0 || len(state.PlayerCards) != 2 {
+		t.Errorf("Failed guesses changed the game: %+v", state)
+	}
+	if state := c.state("OPEN", "Alice"); len(state.Players) != 1 {
+		t.Errorf("Failed joins changed the room: %v", state.Players)
+	}
+}
+
+func TestE2ECORS(t *testing.T) {
+	c := newE2EServer(t)
+	defer func(origins []string) { config.CORSOrigins = origins }(config.CORSOrigins)
+	config.CORSOrigins = []string{"https://crossclues.example"}
+	CreateRoom("CORS", 3, "Alice")
+
+	checkCORS := func(resp *e2eResponse, allowed bool) {
+		t.Helper()
+		got := resp.Header.Get("Access-Control-Allow-Origin")
+		if allowed && (got != "https://crossclues.example" || resp.Header.Get("Vary") != "Origin") {
+			t.Errorf("%s %s: expected the origin to be allowed, got %q (Vary %q)", resp.Request.Method, resp.Request.URL.Path, got, resp.Header.Get("Vary"))
+		}
+		if !allowed && got != "" {
+			t.Errorf("%s %s: expected no Access-Control-Allow-Origin, got %q", resp.Request.Method, resp.Request.URL.Path, got)
+		}
+		if allowed && resp.Header.Get("Access-Control-Expose-Headers") != requestIDHeader {
+			t.Errorf("Expected %s to be exposed, got %q", requestIDHeader, resp.Header.Get("Access-Control-Expose-Headers"))
+		}
+	}
+
+	// Successes and failures alike carry CORS headers for allowed origins
+	resp := c.do(http.MethodGet, "/api/v1/rooms/CORS/state?playerName=Alice", nil, "Origin", "https://crossclues.example")
+	c.expect(resp, http.StatusOK, "roomCode", "gridSize", "gameStarted", "gameOver", "correctGuesses",
+		"totalCells", "rowWords", "columnWords", "playerCards", "grid", "players")
+	checkCORS(resp, true)
+
+	resp = c.do(http.MethodPost, "/api/v1/rooms/CORS/start", nil, "Origin", "https://crossclues.example")
+	c.expectError(resp, http.StatusBadRequest, CodeNotEnoughPlayers)
+	checkCORS(resp, true)
+
+	resp = c.do(http.MethodGet, "/api/rooms/CORS/state?playerName=Alice", nil, "Origin", "https://evil.example")
+	checkCORS(resp, false)
+
+	// Preflight requests are answered for every route, without running it
+	for _, path := range []string{"/api/v1/rooms", "/api/v1/rooms/CORS/join", "/api/rooms/CORS/guess"} {
+		resp = c.do(http.MethodOptions, path, nil,
+			"Origin", "https://crossclues.example",
+			"Access-Control-Request-Method", "POST",
+			"Access-Control-Request-Headers", "Content-Type")
+		if resp.StatusCode != http.StatusOK {
+			t.Errorf("OPTIONS %s: expected 200, got %d", path, resp.StatusCode)
+		}
+		checkCORS(resp, true)
+		if methods := resp.Header.Get("Access-Control-Allow-Methods"); !strings.Contains(methods, "POST") {
+			t.Errorf("OPTIONS %s: expected POST to be allowed, got %q", path, methods)
+		}
+		if headers := resp.Header.Get("Access-Control-Allow-Headers"); !strings.Contains(headers, "Content-Type") {
+			t.Errorf("OPTIONS %s: expected Content-Type to be allowed, got %q", path, headers)
+		}
+	}
+	if state := c.state("CORS", "Alice"); len(state.Players) != 1 {
+		t.Errorf("Expected preflight requests to leave the room alone, got %v", state.Players)
+	}
+}
+
+// TestE2EConcurrentClients has many clients race to join one room, start it
+// and play their cards at the same time. Every request must either succeed
+// or fail with an error the game allows for, and the game must end with
+// every cell resolved exactly once.
+func TestE2EConcurrentClients(t *testing.T) {
+	c := newE2EServer(t)
+	c.expect(c.do(http.MethodPost, "/api/v1/rooms", CreateRoomRequest{RoomCode: "RACE", PlayerName: "Host", GridSize: 5}),
+		http.StatusCreated, "roomCode", "playerName", "message")
+
+	const clients = 16 // More than fit in the room
+	var (
+		wg     sync.WaitGroup
+		mu     sync.Mutex
+		joined = []string{"Host"}
+		errs   = make(map[string]int) // error code -> count
+	)
+	record := func(resp *e2eResponse, allowed ...string) bool {
+		if resp.StatusCode < 300 {
+			return true
+		}
+		code, _ := resp.Body["code"].(string)
+		mu.Lock()
+		errs[code]++
+		mu.Unlock()
+		for _, a := range allowed {
+			if code == a {
+				return false
+			}
+		}
+		t.Errorf("%s %s: unexpected %d %s", resp.Request.Method, resp.Request.URL.Path, resp.StatusCode, code)
+		return false
+	}
+
+	for i := 0; i < clients; i++ {
+		wg.Add(1)
+		go func(name string) {
+			defer wg.Done()
+			resp := c.do(http.MethodPost, "/api/v1/rooms/race/join", JoinRoomRequest{PlayerName: name})
+			if record(resp, CodeRoomFull) {
+				mu.Lock()
+				joined = append(joined, name)
+				mu.Unlock()
+			}
+		}(fmt.Sprintf("Player %d", i))
+	}
+	wg.Wait()
+	if len(joined) != config.MaxPlayersPerRoom || errs[CodeRoomFull] != clients+1-len(joined) {
+		t.Fatalf("Expected %d players to fit, got %d with %d turned away", config.MaxPlayersPerRoom, len(joined), errs[CodeRoomFull])
+	}
+
+	// Everyone starts the game at once; later starts deal fresh hands, which
+	// the players pick up on their next state read
+	for range joined {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			record(c.do(http.MethodPost, "/api/v1/rooms/RACE/start", nil))
+		}()
+	}
+	wg.Wait()
+
+	// Each player plays its own hand, and also tries cards that someone else
+	// has just been dealt
+	for i, name := range joined {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for moves := 0; moves < 1000; moves++ {
+				resp := c.do(http.MethodGet, "/api/v1/rooms/RACE/state?playerName="+url.QueryEscape(name), nil)
+				if !record(resp) {
+					return
+				}
+				var state GameStateResponse
+				data, _ := json.Marshal(resp.Body)
+				json.Unmarshal(data, &state)
+				if state.GameOver {
+					return
+				}
+
+				card := Card{Row: moves % 5, Column: i % 5}
+				if len(state.PlayerCards) > 0 {
+					card = state.PlayerCards[0]
+				}
+				record(c.do(http.MethodPost, "/api/v1/rooms/RACE/guess", GuessRequest{
+					PlayerName: name, Row: card.Row, Column: card.Column, Correct: moves%2 == 0,
+				}), CodeNoCard, CodeGameOver)
+			}
+			t.Errorf("%s: game did not finish in 1000 moves", name)
+		}()
+	}
+	wg.Wait()
+
+	state := c.state("RACE", "Host")
+	if !state.GameOver {
+		t.Fatal("Expected the game to be over")
+	}
+	room, _ := getRoom("RACE")
+	room.mu.RLock()
+	defer room.mu.RUnlock()
+	if err := checkInvariants(room); err != nil {
+		t.Error(err)
+	}
+	t.Logf("Errors by code: %v", errs)
+}
@@ -0,0 +1,176 @@
+package main
+
+import (
+	"errors"
+	"log/slog"
+	"net/http"
+)
+
+// Error is a domain error with a stable, machine-readable code. Clients
+// should branch on Code rather than on the message text.
+type Error struct {
+	Code    string
+	Message string
+	Details map[string]any
+}
+
+func newError(code, message string) *Error {
+	return &Error{Code: code, Message: message}
+}
+
+func (e *Error) Error() string {
+	return e.Message
+}
+
+// Is makes errors.Is match any *Error with the same code, so an error
+// returned from WithDetails still matches its sentinel
+func (e *Error) Is(target error) bool {
+	t, ok := target.(*Error)
+	return ok && t.Code == e.Code
+}
+
+// WithDetails returns a copy of e carrying extra details for the client
+func (e *Error) WithDetails(details map[string]any) *Error {
+	return &Error{Code: e.Code, Message: e.Message, Details: details}
+}
+
+// Error codes
+const (
+	CodeRoomNotFound       = "ROOM_NOT_FOUND"
+	CodeRoomExists         = "ROOM_EXISTS"
+	CodeRoomFull           = "ROOM_FULL"
+	CodeTooManyRooms       = "TOO_MANY_ROOMS"
+	CodeNoFreeRoomCode     = "NO_FREE_ROOM_CODE"
+	CodeInvalidRoomCode    = "INVALID_ROOM_CODE"
+	CodeUnknownWordPack    = "UNKNOWN_WORD_PACK"
+	CodeInvalidGridSize    = "INVALID_GRID_SIZE"
+	CodeInvalidMaxPlayers  = "INVALID_MAX_PLAYERS"
+	CodeInvalidPassword    = "INVALID_PASSWORD"
+	CodeWrongPassword      = "WRONG_PASSWORD"
+	CodePlayerExists       = "PLAYER_EXISTS"
+	CodePlayerNotFound     = "PLAYER_NOT_FOUND"
+	CodePlayerNameRequired = "PLAYER_NAME_REQUIRED"
+	CodePlayerNameTooLong  = "PLAYER_NAME_TOO_LONG"
+	CodePlayerNameInvalid  = "PLAYER_NAME_INVALID"
+	CodePlayerNameReserved = "PLAYER_NAME_RESERVED"
+	CodeGameNotStarted     = "GAME_NOT_STARTED"
+	CodeGameOver           = "GAME_OVER"
+	CodeNotEnoughPlayers   = "NOT_ENOUGH_PLAYERS"
+	CodeNoCard             = "NO_CARD"
+	CodeBotNotFound        = "BOT_NOT_FOUND"
+	CodeTooManyBots        = "TOO_MANY_BOTS"
+	CodeUnknownStrategy    = "UNKNOWN_STRATEGY"
+	CodeNoClue             = "NO_CLUE"
+	CodeInvalidClue        = "INVALID_CLUE"
+	CodeInvalidCell        = "INVALID_CELL"
+	CodeInvalidFormat      = "INVALID_FORMAT"
+	CodeNoFinishedGame     = "NO_FINISHED_GAME"
+	CodeInvalidRequest     = "INVALID_REQUEST"
+	CodeNotFound           = "NOT_FOUND"
+	CodeMethodNotAllowed   = "METHOD_NOT_ALLOWED"
+	CodeUnauthorized       = "UNAUTHORIZED"
+	CodeRateLimited        = "RATE_LIMITED"
+	CodeShuttingDown       = "SHUTTING_DOWN"
+	CodeInternal           = "INTERNAL"
+)
+
+// codeStatus maps each error code to its HTTP status
+var codeStatus = map[string]int{
+	CodeRoomNotFound:       http.StatusNotFound,
+	CodeRoomExists:         http.StatusConflict,
+	CodeRoomFull:           http.StatusConflict,
+	CodeTooManyRooms:       http.StatusServiceUnavailable,
+	CodeNoFreeRoomCode:     http.StatusServiceUnavailable,
+	CodeInvalidRoomCode:    http.StatusBadRequest,
+	CodeUnknownWordPack:    http.StatusBadRequest,
+	CodeInvalidGridSize:    http.StatusBadRequest,
+	CodeInvalidMaxPlayers:  http.StatusBadRequest,
+	CodeInvalidPassword:    http.StatusBadRequest,
+	CodeWrongPassword:      http.StatusForbidden,
+	CodePlayerExists:       http.StatusConflict,
+	CodePlayerNotFound:     http.StatusNotFound,
+	CodePlayerNameRequired: http.StatusBadRequest,
+	CodePlayerNameTooLong:  http.StatusBadRequest,
+	CodePlayerNameInvalid:  http.StatusBadRequest,
+	CodePlayerNameReserved: http.StatusBadRequest,
+	CodeGameNotStarted:     http.StatusBadRequest,
+	CodeGameOver:           http.StatusBadRequest,
+	CodeNotEnoughPlayers:   http.StatusBadRequest,
+	CodeNoCard:             http.StatusBadRequest,
+	CodeBotNotFound:        http.StatusNotFound,
+	CodeTooManyBots:        http.StatusConflict,
+	CodeUnknownStrategy:    http.StatusBadRequest,
+	CodeNoClue:             http.StatusNotFound,
+	CodeInvalidClue:        http.StatusBadRequest,
+	CodeInvalidCell:        http.StatusBadRequest,
+	CodeInvalidFormat:      http.StatusBadRequest,
+	CodeNoFinishedGame:     http.StatusNotFound,
+	CodeInvalidRequest:     http.StatusBadRequest,
+	CodeNotFound:           http.StatusNotFound,
+	CodeMethodNotAllowed:   http.StatusMethodNotAllowed,
+	CodeUnauthorized:       http.StatusUnauthorized,
+	CodeRateLimited:        http.StatusTooManyRequests,
+	CodeShuttingDown:       http.StatusServiceUnavailable,
+	CodeInternal:           http.StatusInternalServerError,
+}
+
+// Errors returned by the room and game functions
+var (
+	ErrRoomNotFound     = newError(CodeRoomNotFound, "room not found")
+	ErrRoomExists       = newError(CodeRoomExists, "room already exists")
+	ErrPlayerExists     = newError(CodePlayerExists, "player name already taken in this room")
+	ErrPlayerNotFound   = newError(CodePlayerNotFound, "player not found in this room")
+	ErrGameNotStarted   = newError(CodeGameNotStarted, "game has not started")
+	ErrGameOver         = newError(CodeGameOver, "game is already over")
+	ErrNotEnoughPlayers = newError(CodeNotEnoughPlayers, "need at least 2 players to start")
+	ErrNoCard           = newError(CodeNoCard, "player does not have a card for this cell")
+	ErrTooManyRooms     = newError(CodeTooManyRooms, "server has reached its room limit")
+	ErrNoFreeRoomCode   = newError(CodeNoFreeRoomCode, "no free room code was found; try again or choose a code")
+	ErrRoomFull         = newError(CodeRoomFull, "room is full")
+	ErrUnknownWordPack  = newError(CodeUnknownWordPack, "unknown word pack")
+	ErrInvalidRoomCode  = newError(CodeInvalidRoomCode, "room code must be 3-24 letters, digits, '-' or '_'")
+	ErrInvalidPassword  = newError(CodeInvalidPassword, "room password must be 4-64 characters")
+	ErrWrongPassword    = newError(CodeWrongPassword, "incorrect room password")
+
+	ErrPlayerNameRequired = newError(CodePlayerNameRequired, "player name is required")
+	ErrPlayerNameTooLong  = newError(CodePlayerNameTooLong, "player name must be at most 24 characters")
+	ErrPlayerNameInvalid  = newError(CodePlayerNameInvalid, "player name contains invalid characters")
+	ErrPlayerNameReserved = newError(CodePlayerNameReserved, "player names starting with \"Bot \" are reserved for bots")
+	ErrInvalidMaxPlayers  = newError(CodeInvalidMaxPlayers, "max players is outside the allowed range")
+
+	ErrBotNotFound     = newError(CodeBotNotFound, "bot not found in this room")
+	ErrTooManyBots     = newError(CodeTooManyBots, "room has reached its bot limit")
+	ErrUnknownStrategy = newError(CodeUnknownStrategy, "unknown bot strategy")
+	ErrNoClue          = newError(CodeNoClue, "no clue fits this card")
+	ErrInvalidClue     = newError(CodeInvalidClue, "clue must be a single word of at most 32 characters")
+	ErrNoFinishedGame  = newError(CodeNoFinishedGame, "no game has finished in this room yet")
+)
+
+// Request-level errors raised by the HTTP handlers
+var (
+	ErrInvalidBody      = newError(CodeInvalidRequest, "invalid request body")
+	ErrEndpointNotFound = newError(CodeNotFound, "endpoint not found")
+	ErrMethodNotAllowed = newError(CodeMethodNotAllowed, "method not allowed")
+	ErrUnauthorized     = newError(CodeUnauthorized, "unauthorized")
+	ErrRateLimited      = newError(CodeRateLimited, "too many requests, please slow down")
+	ErrShuttingDown     = newError(CodeShuttingDown, "server is shutting down")
+	ErrInvalidGridSize  = newError(CodeInvalidGridSize, "grid size is outside the allowed range")
+	ErrInvalidCell      = newError(CodeInvalidCell, "invalid row or column")
+	ErrInvalidFormat    = newError(CodeInvalidFormat, "format must be json or csv")
+	ErrInternal         = newError(CodeInternal, "internal server error")
+)
+
+// httpError resolves err to a domain error and its HTTP status. Errors that
+// aren't domain errors are reported as internal errors.
+func httpError(err error) (*Error, int) {
+	var e *Error
+	if !errors.As(err, &e) {
+		slog.Error("Unexpected error", "err", err)
+		e = ErrInternal
+	}
+	status, ok := codeStatus[e.Code]
+	if !ok {
+		status = http.StatusInternalServerError
+	}
+	return e, status
+}
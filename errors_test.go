@@ -0,0 +1,79 @@
+package main
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+)
+
+func TestErrorIs(t *testing.T) {
+	err := ErrInvalidGridSize.WithDetails(map[string]any{"min": 3})
+	if !errors.Is(err, ErrInvalidGridSize) {
+		t.Error("Expected an error with details to match its sentinel")
+	}
+	if errors.Is(err, ErrInvalidCell) {
+		t.Error("Expected errors with different codes not to match")
+	}
+	if errors.Is(ErrInvalidFormat, ErrInvalidBody) {
+		t.Error("Expected a bad history format not to match a bad request body")
+	}
+	if !errors.Is(fmt.Errorf("joining: %w", ErrRoomFull), ErrRoomFull) {
+		t.Error("Expected a wrapped error to match its sentinel")
+	}
+}
+
+func TestHTTPError(t *testing.T) {
+	tests := []struct {
+		err    error
+		code   string
+		status int
+	}{
+		{ErrRoomNotFound, CodeRoomNotFound, http.StatusNotFound},
+		{ErrRoomFull, CodeRoomFull, http.StatusConflict},
+		{ErrGameOver, CodeGameOver, http.StatusBadRequest},
+		{ErrNoCard, CodeNoCard, http.StatusBadRequest},
+		{ErrWrongPassword, CodeWrongPassword, http.StatusForbidden},
+		{ErrTooManyRooms, CodeTooManyRooms, http.StatusServiceUnavailable},
+		{ErrNoFreeRoomCode, CodeNoFreeRoomCode, http.StatusServiceUnavailable},
+		{fmt.Errorf("wrapped: %w", ErrPlayerNotFound), CodePlayerNotFound, http.StatusNotFound},
+		{errors.New("boom"), CodeInternal, http.StatusInternalServerError},
+	}
+	for _, tt := range tests {
+		e, status := httpError(tt.err)
+		if e.Code != tt.code || status != tt.status {
+			t.Errorf("%v: expected %s/%d, got %s/%d", tt.err, tt.code, tt.status, e.Code, status)
+		}
+	}
+}
+
+func TestErrorResponseBody(t *testing.T) {
+	ClearRooms()
+
+	body := strings.NewReader(`{"playerName": "Alice"}`)
+	req := httptest.NewRequest(http.MethodPost, "/api/rooms/NOPE/join", body)
+	rec := httptest.NewRecorder()
+	handleJoinRoom(rec, req)
+
+	if rec.Code != http.StatusNotFound {
+		t.Fatalf("Expected 404, got %d", rec.Code)
+	}
+	var resp ErrorResponse
+	if err := json.NewDecoder(rec.Body).Decode(&resp); err != nil {
+		t.Fatalf("Expected a JSON body, got %v", err)
+	}
+	if resp.Code != CodeRoomNotFound {
+		t.Errorf("Expected code %s, got %s", CodeRoomNotFound, resp.Code)
+	}
+
+	rec = httptest.NewRecorder()
+	writeError(rec, gridSizeError())
+	resp = ErrorResponse{}
+	json.NewDecoder(rec.Body).Decode(&resp)
+	if resp.Code != CodeInvalidGridSize || resp.Details["max"] != float64(config.MaxGridSize) {
+		t.Errorf("Expected INVALID_GRID_SIZE with max %d, got %+v", config.MaxGridSize, resp)
+	}
+}
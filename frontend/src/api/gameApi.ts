@@ -1,9 +1,9 @@
 // Game API - communicates with Go backend
 
 // Use relative URL for production (served from same origin) or localhost for development
-const API_BASE = import.meta.env.DEV ? "http://localhost:8080/api" : "/api";
+const API_BASE = import.meta.env.DEV ? "http://localhost:8080/api/v1" : "/api/v1";
 
-// Types matching Go backend schema
+// Types matching Go backend schema; the contract is openapi.json in the repo root
 
 export interface Card {
   row: number;
@@ -25,6 +25,8 @@ export interface GameState {
   playerCards: Card[];
   grid: CellResponse[][];
   players: string[];
+  // Result summary for pasting into chat, once the game is over
+  share?: string;
 }
 
 export interface CreateRoomResponse {
@@ -46,8 +48,68 @@ export interface GuessResponse {
   gameOver: boolean;
 }
 
+export interface LobbyRoom {
+  roomCode: string;
+  playerCount: number;
+  maxPlayers: number;
+  gridSize: number;
+  wordPack: string;
+  private: boolean;
+  ageSeconds: number;
+}
+
+export interface QuickJoinResponse {
+  roomCode: string;
+  playerName: string;
+  cardsDealt: number;
+  created: boolean;
+  message: string;
+}
+
+// Stable error codes; branch on these rather than on the message text
+export type ErrorCode =
+  | "ROOM_NOT_FOUND"
+  | "ROOM_EXISTS"
+  | "ROOM_FULL"
+  | "TOO_MANY_ROOMS"
+  | "NO_FREE_ROOM_CODE"
+  | "INVALID_ROOM_CODE"
+  | "UNKNOWN_WORD_PACK"
+  | "INVALID_GRID_SIZE"
+  | "INVALID_MAX_PLAYERS"
+  | "INVALID_PASSWORD"
+  | "WRONG_PASSWORD"
+  | "PLAYER_EXISTS"
+  | "PLAYER_NOT_FOUND"
+  | "PLAYER_NAME_REQUIRED"
+  | "PLAYER_NAME_TOO_LONG"
+  | "PLAYER_NAME_INVALID"
+  | "PLAYER_NAME_RESERVED"
+  | "GAME_NOT_STARTED"
+  | "GAME_OVER"
+  | "NOT_ENOUGH_PLAYERS"
+  | "NO_CARD"
+  | "BOT_NOT_FOUND"
+  | "TOO_MANY_BOTS"
+  | "UNKNOWN_STRATEGY"
+  | "NO_CLUE"
+  | "INVALID_CLUE"
+  | "INVALID_CELL"
+  | "INVALID_FORMAT"
+  | "NO_FINISHED_GAME"
+  | "INVALID_REQUEST"
+  | "NOT_FOUND"
+  | "METHOD_NOT_ALLOWED"
+  | "UNAUTHORIZED"
+  | "RATE_LIMITED"
+  | "SHUTTING_DOWN"
+  | "INTERNAL";
+
 export interface ErrorResponse {
   error: string;
+  code: ErrorCode;
+  details?: Record<string, unknown>;
+  requestId?: string;
 }
 
 // --- Fetch game state from backend ---
@@ -92,12 +154,73 @@ export async function postGuess(payload: {
   return response.json();
 }
 
+// --- Suggest a clue for a card in the player's hand ---
+export interface ClueSuggestion {
+  roomCode: string;
+  row: number;
+  column: number;
+  rowWord: string;
+  columnWord: string;
+  clue: string;
+  alternatives: string[];
+}
+
+export async function suggestClue(payload: {
+  roomCode: string;
+  playerName: string;
+  row: number;
+  column: number;
+}): Promise<ClueSuggestion> {
+  const params = new URLSearchParams({
+    playerName: payload.playerName,
+    row: String(payload.row),
+    column: String(payload.column),
+  });
+  const response = await fetch(
+    `${API_BASE}/rooms/${payload.roomCode}/clue?${params}`
+  );
+  if (!response.ok) {
+    const errorData: ErrorResponse = await response.json();
+    throw new Error(errorData.error || "Failed to suggest a clue");
+  }
+  return response.json();
+}
+
+// --- Game history export ---
+// Finished games in a room, as a JSON or CSV file. Only the room's players
+// may download it; a private room also needs its password.
+export async function downloadHistory(payload: {
+  roomCode: string;
+  playerName: string;
+  password?: string;
+  format: "json" | "csv";
+}): Promise<Blob> {
+  const params = new URLSearchParams({
+    playerName: payload.playerName,
+    format: payload.format,
+  });
+  if (payload.password) {
+    params.set("password", payload.password);
+  }
+  const response = await fetch(
+    `${API_BASE}/rooms/${payload.roomCode}/history?${params}`
+  );
+  if (!response.ok) {
+    const errorData: ErrorResponse = await response.json();
+    throw new Error(errorData.error || "Failed to download the history");
+  }
+  return response.blob();
+}
+
 // --- Create room API ---
+// The server generates a room code when none is supplied.
 export async function createRoom(payload: {
-  roomCode: string;
+  roomCode?: string;
   playerName: string;
   gridSize?: number;
-}): Promise<{ success: boolean; message: string }> {
+  password?: string;
+  public?: boolean;
+}): Promise<{ success: boolean; message: string; roomCode?: string }> {
   const response = await fetch(`${API_BASE}/rooms`, {
     method: "POST",
     headers: { "Content-Type": "application/json" },
@@ -105,6 +228,8 @@ export async function createRoom(payload: {
       roomCode: payload.roomCode,
       playerName: payload.playerName,
       gridSize: payload.gridSize || 5,
+      password: payload.password || undefined,
+      public: payload.public || undefined,
     }),
   });
   if (!response.ok) {
@@ -112,18 +237,22 @@ export async function createRoom(payload: {
     return { success: false, message: errorData.error };
   }
   const data: CreateRoomResponse = await response.json();
-  return { success: true, message: data.message };
+  return { success: true, message: data.message, roomCode: data.roomCode };
 }
 
 // --- Join room API ---
 export async function joinRoom(payload: {
   roomCode: string;
   playerName: string;
+  password?: string;
 }): Promise<{ success: boolean; message: string; cardsDealt?: number }> {
   const response = await fetch(`${API_BASE}/rooms/${payload.roomCode}/join`, {
     method: "POST",
     headers: { "Content-Type": "application/json" },
-    body: JSON.stringify({ playerName: payload.playerName }),
+    body: JSON.stringify({
+      playerName: payload.playerName,
+      password: payload.password || undefined,
+    }),
   });
   if (!response.ok) {
     const errorData: ErrorResponse = await response.json();
@@ -166,3 +295,32 @@ export async function startGame(
   const data = await response.json();
   return { success: true, message: data.message };
 }
+
+// --- List public rooms waiting for players ---
+export async function fetchLobby(): Promise<LobbyRoom[]> {
+  const response = await fetch(`${API_BASE}/lobby`);
+  if (!response.ok) {
+    const errorData: ErrorResponse = await response.json();
+    throw new Error(errorData.error || "Failed to fetch lobby");
+  }
+  const data: { rooms: LobbyRoom[] } = await response.json();
+  return data.rooms;
+}
+
+// --- Join the best open public room, or create one ---
+export async function quickJoin(payload: {
+  playerName: string;
+  gridSize?: number;
+}): Promise<{ success: boolean; message: string; roomCode?: string }> {
+  const response = await fetch(`${API_BASE}/lobby/quick-join`, {
+    method: "POST",
+    headers: { "Content-Type": "application/json" },
+    body: JSON.stringify(payload),
+  });
+  if (!response.ok) {
+    const errorData: ErrorResponse = await response.json();
+    return { success: false, message: errorData.error };
+  }
+  const data: QuickJoinResponse = await response.json();
+  return { success: true, message: data.message, roomCode: data.roomCode };
+}
@@ -1,11 +1,17 @@
-import React, { Fragment } from "react";
-import { useSearchParams, useNavigate } from "react-router-dom";
+import React, { Fragment, useState } from "react";
+import { useSearchParams, useNavigate, useLocation } from "react-router-dom";
 import { Container, Button, Navbar, Spinner, Alert } from "react-bootstrap";
 import { GridButton } from "../components/GridButton";
 import { ClueLabel } from "../components/ClueLabel";
 import { ActionButton } from "../components/ActionButton";
 import { useGameState } from "../hooks/useGameState";
-import { postGuess, startGame, leaveRoom } from "../api/gameApi";
+import {
+  postGuess,
+  startGame,
+  leaveRoom,
+  suggestClue,
+  downloadHistory,
+} from "../api/gameApi";
 import type { Card } from "../api/gameApi";
 import "./GameScreen.css";
 
@@ -21,16 +27,52 @@ export const GameScreen: React.FC = () => {
   const navigate = useNavigate();
   const roomCode = searchParams.get("room") || "";
   const playerName = searchParams.get("player") || "";
+  // A private room's password, handed on by the lobby rather than kept in
+  // the URL
+  const location = useLocation();
+  const password =
+    (location.state as { password?: string } | null)?.password || "";
   const { gameState, error, loading, refetch } = useGameState(
     roomCode,
     playerName
   );
+  // The summary last copied, so a new game's result shows as not copied
+  const [copiedShare, setCopiedShare] = useState("");
 
   const handleLeaveRoom = async () => {
     await leaveRoom({ roomCode, playerName });
     navigate("/");
   };
 
+  const handleCopyShare = async () => {
+    if (!gameState?.share) return;
+    try {
+      await navigator.clipboard.writeText(gameState.share);
+      setCopiedShare(gameState.share);
+    } catch {
+      alert(gameState.share);
+    }
+  };
+
+  const handleDownloadHistory = async (format: "json" | "csv") => {
+    try {
+      const blob = await downloadHistory({
+        roomCode,
+        playerName,
+        password,
+        format,
+      });
+      const url = URL.createObjectURL(blob);
+      const link = document.createElement("a");
+      link.href = url;
+      link.download = `crossclues-${roomCode}-history.${format}`;
+      link.click();
+      URL.revokeObjectURL(url);
+    } catch (err) {
+      alert(err instanceof Error ? err.message : "Failed to download");
+    }
+  };
+
   const handleStartGame = async () => {
     const res = await startGame(roomCode);
     if (!res.success) {
@@ -74,6 +116,23 @@ export const GameScreen: React.FC = () => {
     }
   };
 
+  const handleSuggestClue = async (card: Card) => {
+    try {
+      const res = await suggestClue({
+        roomCode,
+        playerName,
+        row: card.row,
+        column: card.column,
+      });
+      const others = res.alternatives.length
+        ? ` (or: ${res.alternatives.join(", ")})`
+        : "";
+      alert(`${res.rowWord} + ${res.columnWord}: try "${res.clue}"${others}`);
+    } catch (err) {
+      alert(err instanceof Error ? err.message : "Failed to suggest a clue");
+    }
+  };
+
   // Loading state
   if (loading && !gameState) {
     return (
@@ -179,6 +238,42 @@ export const GameScreen: React.FC = () => {
                 </strong>{" "}
                 out of <strong>{gridSize * gridSize}</strong> tiles correctly!
               </p>
+              {gameState?.share && (
+                <>
+                  <pre className="share-text mt-2 mb-1">{gameState.share}</pre>
+                  <Button
+                    variant="outline-success"
+                    size="sm"
+                    onClick={handleCopyShare}
+                  >
+                    {copiedShare === gameState.share
+                      ? "Copied!"
+                      : "Copy result"}
+                  </Button>
+                </>
+              )}
+              <p className="mb-0 mt-1 small">
+                Download this room's games as{" "}
+                <Alert.Link
+                  href="#"
+                  onClick={(e) => {
+                    e.preventDefault();
+                    handleDownloadHistory("csv");
+                  }}
+                >
+                  CSV
+                </Alert.Link>{" "}
+                or{" "}
+                <Alert.Link
+                  href="#"
+                  onClick={(e) => {
+                    e.preventDefault();
+                    handleDownloadHistory("json");
+                  }}
+                >
+                  JSON
+                </Alert.Link>
+              </p>
             </Alert>
           )}
 
@@ -253,12 +348,23 @@ export const GameScreen: React.FC = () => {
           {gameStarted && !gameOver && playerCards.length > 0 && (
             <div className="player-cards-container">
               {playerCards.map((card, idx) => (
-                <ActionButton
+                <div
                   key={`card-${idx}`}
-                  label={getCardLabel(card)}
-                  onClick={() => handleGuess(card)}
-                  onDiscard={() => handleDiscard(card)}
-                />
+                  className="d-inline-flex flex-column align-items-center"
+                >
+                  <ActionButton
+                    label={getCardLabel(card)}
+                    onClick={() => handleGuess(card)}
+                    onDiscard={() => handleDiscard(card)}
+                  />
+                  <Button
+                    variant="link"
+                    size="sm"
+                    onClick={() => handleSuggestClue(card)}
+                  >
+                    Suggest a clue
+                  </Button>
+                </div>
               ))}
             </div>
           )}
@@ -1,16 +1,23 @@
 import React from "react";
 import { useState } from "react";
-import { useNavigate } from "react-router-dom";
+import { useNavigate, useSearchParams } from "react-router-dom";
 import { Container, Form, Button, Row, Col, Card } from "react-bootstrap";
-import { createRoom, joinRoom } from "../api/gameApi";
+import { createRoom, joinRoom, quickJoin } from "../api/gameApi";
 
 const MIN_GRID_SIZE = 3;
 const MAX_GRID_SIZE = 5;
 const DEFAULT_GRID_SIZE = 5;
 
 export const RoomCreation: React.FC = () => {
-  const [joinRoomCode, setJoinRoomCode] = useState("");
+  // Links in shared results point here with the room code filled in
+  const [searchParams] = useSearchParams();
+  const [joinRoomCode, setJoinRoomCode] = useState(
+    searchParams.get("room") || ""
+  );
   const [joinPlayerName, setJoinPlayerName] = useState("");
+  const [joinPassword, setJoinPassword] = useState("");
+  const [createPassword, setCreatePassword] = useState("");
+  const [createPublic, setCreatePublic] = useState(false);
   const [createPlayerName, setCreatePlayerName] = useState("");
   const [gridSize, setGridSize] = useState(DEFAULT_GRID_SIZE);
   const navigate = useNavigate();
@@ -20,22 +27,19 @@ export const RoomCreation: React.FC = () => {
   const handleCreateRoom = async (e: React.FormEvent) => {
     e.preventDefault();
     if (createPlayerName.trim()) {
-      // Generate a random room code
-      const newRoomCode = Math.random()
-        .toString(36)
-        .substring(2, 8)
-        .toUpperCase();
-      // Call API to create the room on the server
+      // Call API to create the room; the server picks the room code
       const res = await createRoom({
-        roomCode: newRoomCode,
         playerName: createPlayerName,
         gridSize,
+        password: createPassword,
+        public: createPublic,
       });
-      if (res.success) {
+      if (res.success && res.roomCode) {
         navigate(
-          `/game?room=${newRoomCode}&player=${encodeURIComponent(
+          `/game?room=${res.roomCode}&player=${encodeURIComponent(
             createPlayerName
-          )}`
+          )}`,
+          { state: { password: createPassword } }
         );
       } else {
         alert(res.message || "Failed to create room");
@@ -49,12 +53,14 @@ export const RoomCreation: React.FC = () => {
       const res = await joinRoom({
         roomCode: joinRoomCode,
         playerName: joinPlayerName,
+        password: joinPassword,
       });
       if (res.success) {
         navigate(
           `/game?room=${joinRoomCode}&player=${encodeURIComponent(
             joinPlayerName
-          )}`
+          )}`,
+          { state: { password: joinPassword } }
         );
       } else {
         alert(res.message || "Failed to join room");
@@ -62,6 +68,23 @@ export const RoomCreation: React.FC = () => {
     }
   };
 
+  const handleQuickJoin = async () => {
+    if (joinPlayerName.trim()) {
+      const res = await quickJoin({ playerName: joinPlayerName });
+      if (res.success && res.roomCode) {
+        navigate(
+          `/game?room=${res.roomCode}&player=${encodeURIComponent(
+            joinPlayerName
+          )}`
+        );
+      } else {
+        alert(res.message || "Failed to find a room");
+      }
+    } else {
+      alert("Enter your name to quick join");
+    }
+  };
+
   return (
     <div className="min-vh-100 d-flex align-items-center justify-content-center bg-gradient">
       <Container>
@@ -112,6 +135,20 @@ export const RoomCreation: React.FC = () => {
                       className="text-uppercase"
                     />
                   </Form.Group>
+                  <Form.Group className="mb-4">
+                    <Form.Label className="fw-bold">
+                      Room Password (if private)
+                    </Form.Label>
+                    <Form.Control
+                      type="password"
+                      value={joinPassword}
+                      onChange={(e: React.ChangeEvent<HTMLInputElement>) =>
+                        setJoinPassword(e.target.value)
+                      }
+                      placeholder="Leave blank for public rooms"
+                      size="lg"
+                    />
+                  </Form.Group>
                   <Button
                     type="submit"
                     variant="success"
@@ -120,6 +157,15 @@ export const RoomCreation: React.FC = () => {
                   >
                     Join Room
                   </Button>
+                  <Button
+                    type="button"
+                    variant="outline-success"
+                    size="lg"
+                    className="w-100 fw-bold mt-2"
+                    onClick={handleQuickJoin}
+                  >
+                    Quick Join a Public Room
+                  </Button>
                 </Form>
               </Card.Body>
             </Card>
@@ -164,6 +210,31 @@ export const RoomCreation: React.FC = () => {
                       </span>
                     </div>
                   </Form.Group>
+                  <Form.Group className="mb-4">
+                    <Form.Label className="fw-bold">
+                      Room Password (optional)
+                    </Form.Label>
+                    <Form.Control
+                      type="password"
+                      value={createPassword}
+                      onChange={(e: React.ChangeEvent<HTMLInputElement>) =>
+                        setCreatePassword(e.target.value)
+                      }
+                      placeholder="Set a password to make the room private"
+                      size="lg"
+                    />
+                  </Form.Group>
+                  <Form.Group className="mb-4">
+                    <Form.Check
+                      type="switch"
+                      id="create-public"
+                      label="List in the public lobby"
+                      checked={createPublic}
+                      onChange={(e: React.ChangeEvent<HTMLInputElement>) =>
+                        setCreatePublic(e.target.checked)
+                      }
+                    />
+                  </Form.Group>
                   <Button
                     type="submit"
                     variant="primary"
@@ -0,0 +1,175 @@
+package main
+
+import (
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+)
+
+// Fuzz targets. Without -fuzz they run only their seed corpus, as part of
+// the normal test run:
+//
+//	go test -run=XXX -fuzz=FuzzRouter -fuzztime=1m
+//	go test -run=XXX -fuzz=FuzzGameOps -fuzztime=1m
+
+const fuzzAdminSecret = "fuzz-secret"
+
+// checkAllRooms checks the invariants of every room
+func checkAllRooms() error {
+	roomsMu.RLock()
+	all := make([]*Room, 0, len(rooms))
+	for _, room := range rooms {
+		all = append(all, room)
+	}
+	roomsMu.RUnlock()
+
+	for _, room := range all {
+		room.mu.RLock()
+		err := checkInvariants(room)
+		room.mu.RUnlock()
+		if err != nil {
+			return fmt.Errorf("room %s: %w", room.RoomCode, err)
+		}
+	}
+	return nil
+}
+
+// FuzzRouter sends arbitrary requests to the API, with and without admin
+// credentials, against a room with a game in progress. No request may crash
+// the server, be answered with a 5xx, or leave a room in a broken state.
+func FuzzRouter(f *testing.F) {
+	seeds := []struct {
+		method, path, body string
+	}{
+		{"POST", "/api/v1/rooms", `{"playerName": "Carol", "gridSize": 4}`},
+		{"POST", "/api/v1/rooms", `{"playerName": "", "gridSize": -1}`},
+		{"POST", "/api/v1/rooms", `{"roomCode": "fuzz", "playerName": "Dave"}`},
+		{"POST", "/api/v1/rooms/FUZZ/join", `{"playerName": "Carol"}`},
+		{"POST", "/api/v1/rooms/FUZZ/join", `{"playerName": "alice"}`},
+		{"POST", "/api/v1/rooms/FUZZ/leave", `{"playerName": "Bob"}`},
+		{"POST", "/api/v1/rooms/FUZZ/start", ``},
+		{"POST", "/api/v1/rooms/FUZZ/guess", `{"playerName": "Alice", "row": 0, "column": 0, "correct": true}`},
+		{"POST", "/api/v1/rooms/FUZZ/guess", `{"playerName": "Alice", "row": -1, "column": 99}`},
+		{"POST", "/api/v1/rooms/FUZZ/guess", `{"playerName": "Alice", "row": 1e99}`},
+		{"POST", "/api/v1/rooms/FUZZ/guess", `{"playerName": "Alice", "row": 4, "column": 5}`},
+		{"POST", "/api/rooms/FUZZ/guess", `[`},
+		{"GET", "/api/v1/rooms/FUZZ/state?playerName=Alice", ``},
+		{"GET", "/api/v1/rooms/FUZZ/clue?playerName=Alice&row=0&column=9", ``},
+		{"GET", "/api/v1/rooms/FUZZ/matches?clue=cold&all=1", ``},
+		{"POST", "/api/v1/rooms/FUZZ/bots", `{"playerName": "Alice", "strategy": "discard", "thinkMs": 1}`},
+		{"DELETE", "/api/v1/rooms/FUZZ/bots/Bot%201", `{"playerName": "Alice"}`},
+		{"GET", "/api/v1/lobby", ``},
+		{"POST", "/api/v1/lobby/quick-join", `{"playerName": "Erin"}`},
+		{"GET", "/api/v1/admin/rooms/FUZZ", ``},
+		{"POST", "/api/v1/admin/rooms/FUZZ/end", ``},
+		{"DELETE", "/api/v1/admin/rooms/FUZZ/players/%41lice", ``},
+		{"OPTIONS", "/api/v1/rooms/FUZZ/guess", ``},
+		{"PUT", "/api/v1/rooms/FUZZ", `null`},
+		{"GET", "/api/v1/rooms//state", ``},
+		{"GET", "/api/v1/rooms/%2e%2e/state?playerName=%00", ``},
+	}
+	for _, s := range seeds {
+		f.Add(s.method, s.path, s.body, true)
+	}
+	f.Add("GET", "/api/v1/admin/rooms", "", false)
+
+	defer func(secret string) { adminSecret = secret }(adminSecret)
+	adminSecret = fuzzAdminSecret
+	mux := newRouter()
+
+	f.Fuzz(func(t *testing.T, method, path, body string, admin bool) {
+		ClearRooms()
+		defer stopAllBots()
+		CreateRoom("FUZZ", 5, "Alice")
+		JoinRoom("FUZZ", "Bob")
+		StartGame("FUZZ")
+
+		// Requests that could not come off the wire are not interesting
+		req, err := http.NewRequest(method, "http://localhost"+path, strings.NewReader(body))
+		if err != nil || req.URL.Host != "localhost" {
+			t.Skip()
+		}
+		req.RequestURI = req.URL.RequestURI()
+		req.RemoteAddr = "192.0.2.1:1234"
+		if admin {
+			req.Header.Set("Authorization", "Bearer "+fuzzAdminSecret)
+		}
+
+		rec := httptest.NewRecorder()
+		mux.ServeHTTP(rec, req)
+		if rec.Code >= 500 {
+			t.Fatalf("%s %q %q: got %d: %s", method, path, body, rec.Code, rec.Body)
+		}
+		if err := checkAllRooms(); err != nil {
+			t.Fatalf("%s %q %q: %v", method, path, body, err)
+		}
+	})
+}
+
+// FuzzGameOps drives a room with a sequence of operations read from the
+// input, two bytes each, and checks the room's invariants after every one.
+// Guesses use raw coordinates, so they are often off the grid or for cards
+// the player doesn't hold.
+func FuzzGameOps(f *testing.F) {
+	f.Add([]byte{0, 5, 1, 1, 3, 0, 4, 0, 4, 1})
+	f.Add([]byte{0, 3, 1, 1, 1, 2, 1, 3, 3, 0, 4, 0, 2, 1, 4, 0, 4, 0})
+	f.Add([]byte{0, 7, 1, 1, 3, 0, 5, 0, 1, 2, 4, 0, 3, 0})
+	f.Add([]byte{0, 4, 1, 1, 3, 0, 4, 0x80, 4, 0xff, 6, 0, 6, 1, 2, 0, 2, 1})
+	f.Add([]byte{1, 0, 0, 3, 3, 0, 0, 4, 2, 0, 4, 0})
+
+	f.Fuzz(func(t *testing.T, ops []byte) {
+		ClearRooms()
+		const code = "FUZZOPS"
+		player := func(b byte) string { return fmt.Sprintf("P%d", b%8) }
+
+		var log []string
+		for i := 0; i+1 < len(ops); i += 2 {
+			op, arg := ops[i]%7, ops[i+1]
+
+			var desc string
+			var err error
+			switch op {
+			case 0:
+				// Grid sizes just outside the allowed range are tried too
+				size := config.MinGridSize - 1 + int(arg)%(config.MaxGridSize-config.MinGridSize+3)
+				desc = fmt.Sprintf("create %d", size)
+				_, err = CreateRoom(code, size, player(0))
+			case 1:
+				desc = "join " + player(arg)
+				_, err = JoinRoom(code, player(arg))
+			case 2:
+				desc = "leave " + player(arg)
+				err = LeaveRoom(code, player(arg))
+			case 3:
+				desc = "start"
+				err = StartGame(code)
+			case 4:
+				// The player's first card, or a raw cell when arg's top bit is set
+				name, row, col := player(arg), int(int8(arg)>>3), int(arg&7)
+				if arg&0x80 == 0 {
+					if state, stateErr := GetGameState(code, name); stateErr == nil && len(state.PlayerCards) > 0 {
+						row, col = state.PlayerCards[0].Row, state.PlayerCards[0].Column
+					}
+				}
+				desc = fmt.Sprintf("guess %s %d,%d", name, row, col)
+				_, err = SubmitGuess(code, name, row, col, arg&1 == 0)
+			case 5:
+				desc = "end"
+				err = EndGame(code)
+			case 6:
+				desc = "delete"
+				err = DeleteRoom(code)
+			}
+			log = append(log, fmt.Sprintf("%s: %v", desc, err))
+
+			if _, ok := err.(*Error); err != nil && !ok {
+				t.Fatalf("%s returned a non-API error\n%s", desc, joinLines(log))
+			}
+			if err := checkAllRooms(); err != nil {
+				t.Fatalf("after %s: %v\n%s", desc, err, joinLines(log))
+			}
+		}
+	})
+}
@@ -1,7 +1,6 @@
 package main
 
 import (
-	"errors"
 	"math/rand"
 	"sync"
 	"time"
@@ -219,30 +218,30 @@ func init() {
 	rand.Seed(time.Now().UnixNano())
 }
 
-// Error definitions
-var (
-	ErrRoomNotFound     = errors.New("room not found")
-	ErrRoomExists       = errors.New("room already exists")
-	ErrPlayerExists     = errors.New("player name already taken in this room")
-	ErrPlayerNotFound   = errors.New("player not found in this room")
-	ErrGameNotStarted   = errors.New("game has not started")
-	ErrGameOver         = errors.New("game is already over")
-	ErrNotEnoughPlayers = errors.New("need at least 2 players to start")
-	ErrNoCard           = errors.New("player does not have a card for this cell")
-)
-
 // Helper functions
 
+// gridSizeError reports the configured grid size bounds to the client
+func gridSizeError() error {
+	return ErrInvalidGridSize.WithDetails(map[string]any{
+		"min": config.MinGridSize,
+		"max": config.MaxGridSize,
+	})
+}
+
 func getRoom(roomCode string) (*Room, bool) {
 	roomsMu.RLock()
 	defer roomsMu.RUnlock()
-	room, exists := rooms[roomCode]
+	room, exists := rooms[normalizeRoomCode(roomCode)]
 	return room, exists
 }
 
-func shuffleWords() []string {
-	shuffled := make([]string, len(wordList))
-	copy(shuffled, wordList)
+func shuffleWords(pack string) []string {
+	words, ok := wordPacks[pack]
+	if !ok {
+		words = wordList
+	}
+	shuffled := make([]string, len(words))
+	copy(shuffled, words)
 	rand.Shuffle(len(shuffled), func(i, j int) {
 		shuffled[i], shuffled[j] = shuffled[j], shuffled[i]
 	})
@@ -283,6 +282,20 @@ func (r *Room) DrawCard(playerName string) bool {
 	return false
 }
 
+// topUpHands deals from the deck to every player under the per-player
+// limit, which rises when players leave. Hands over the limit, dealt before
+// more players joined, are kept until they are played down. Finished games
+// are left alone so that nobody is dealt cards until the next game starts.
+func (r *Room) topUpHands() {
+	if r.GameOver {
+		return
+	}
+	for _, player := range r.Players {
+		for r.DrawCard(player) {
+		}
+	}
+}
+
 func (r *Room) RemoveCardFromHand(playerName string, row, col int) bool {
 	hand := r.PlayerHands[playerName]
 	for i, card := range hand {
@@ -306,13 +319,31 @@ func (r *Room) CheckGameOver() bool {
 	return true
 }
 
+// HasPlayer reports whether the room has a player with this name, ignoring
+// case and Unicode normalization differences
 func (r *Room) HasPlayer(playerName string) bool {
-	for _, player := range r.Players {
-		if player == playerName {
-			return true
-		}
+	_, ok := r.findPlayer(playerName)
+	return ok
+}
+
+// checkSeated checks that playerName is seated in the room and, if the room
+// is private, that password is its password. It guards requests that only
+// the room's players may make.
+func checkSeated(room *Room, playerName, password string) error {
+	if room.password != nil && !room.password.Matches(password) {
+		return ErrWrongPassword
 	}
-	return false
+	room.mu.RLock()
+	defer room.mu.RUnlock()
+	if !room.HasPlayer(playerName) {
+		return ErrPlayerNotFound
+	}
+	return nil
+}
+
+// IsPrivate reports whether joining the room requires a password
+func (r *Room) IsPrivate() bool {
+	return r.password != nil
 }
 
 func (r *Room) HasCard(playerName string, row, col int) bool {
@@ -326,17 +357,87 @@ func (r *Room) HasCard(playerName string, row, col int) bool {
 
 // Game logic functions
 
+// RoomOptions holds the settings chosen when a room is created
+type RoomOptions struct {
+	GridSize int // 0 uses the configured default
+	WordPack string
+	Password string // Optional; makes the room private
+	Public   bool   // List the room in the lobby
+
+	// MaxPlayers caps the room below the server-wide limit; 0 uses the
+	// server-wide limit
+	MaxPlayers int
+}
+
 // CreateRoom creates a new room with the given code, grid size, and first player
 func CreateRoom(roomCode string, gridSize int, playerName string) (*Room, error) {
+	room, _, err := CreateRoomWithOptions(roomCode, playerName, RoomOptions{GridSize: gridSize})
+	return room, err
+}
+
+// CreateRoomWithOptions creates a new room with the given code, options, and first player.
+// If roomCode is empty a new code is generated. The returned name is the
+// creator's normalized name, safe to use without holding the room's lock.
+func CreateRoomWithOptions(roomCode, playerName string, opts RoomOptions) (room *Room, creator string, err error) {
+	roomCode = normalizeRoomCode(roomCode)
+	if roomCode != "" && !validRoomCode(roomCode) {
+		return nil, "", ErrInvalidRoomCode
+	}
+
+	playerName = normalizePlayerName(playerName)
+	if err = validatePlayerName(playerName); err != nil {
+		return nil, "", err
+	}
+
+	maxPlayers := config.MaxPlayersPerRoom
+	if opts.MaxPlayers != 0 {
+		if opts.MaxPlayers < 2 || (maxPlayers > 0 && opts.MaxPlayers > maxPlayers) {
+			return nil, "", ErrInvalidMaxPlayers
+		}
+		maxPlayers = opts.MaxPlayers
+	}
+
+	gridSize := opts.GridSize
+	if gridSize == 0 {
+		gridSize = config.DefaultGridSize
+	}
+	if gridSize < config.MinGridSize || gridSize > config.MaxGridSize {
+		return nil, "", gridSizeError()
+	}
+
+	wordPack := opts.WordPack
+	if wordPack == "" {
+		wordPack = DefaultWordPack
+	}
+	if _, ok := wordPacks[wordPack]; !ok {
+		return nil, "", ErrUnknownWordPack
+	}
+
+	var password *passwordHash
+	if opts.Password != "" {
+		if !validPassword(opts.Password) {
+			return nil, "", ErrInvalidPassword
+		}
+		password = hashPassword(opts.Password)
+	}
+
 	roomsMu.Lock()
 	defer roomsMu.Unlock()
 
-	if _, exists := rooms[roomCode]; exists {
-		return nil, ErrRoomExists
+	if config.MaxRooms > 0 && len(rooms) >= config.MaxRooms {
+		return nil, "", ErrTooManyRooms
+	}
+
+	if roomCode == "" {
+		if roomCode = generateRoomCode(); roomCode == "" {
+			return nil, "", ErrNoFreeRoomCode
+		}
+	} else if _, exists := rooms[roomCode]; exists {
+		return nil, "", ErrRoomExists
 	}
 
 	// Initialize row and column words
-	words := shuffleWords()
+	words := shuffleWords(wordPack)
 	rowWords := make([]string, gridSize)
 	columnWords := make([]string, gridSize)
 	for i := 0; i < gridSize; i++ {
@@ -359,9 +460,12 @@ func CreateRoom(roomCode string, gridSize int, playerName string) (*Room, error)
 	// Create and shuffle deck
 	cardDeck := createCardDeck(gridSize)
 
-	room := &Room{
+	room = &Room{
 		RoomCode:    roomCode,
 		GridSize:    gridSize,
+		WordPack:    wordPack,
+		Public:      opts.Public,
+		MaxPlayers:  maxPlayers,
 		Players:     []string{playerName},
 		GameStarted: false,
 		GameOver:    false,
@@ -370,6 +474,8 @@ func CreateRoom(roomCode string, gridSize int, playerName string) (*Room, error)
 		Grid:        grid,
 		CardDeck:    cardDeck,
 		PlayerHands: make(map[string][]Card),
+		CreatedAt:   time.Now(),
+		password:    password,
 	}
 
 	// Deal cards to first player (2 cards since only 1 player)
@@ -378,35 +484,61 @@ func CreateRoom(roomCode string, gridSize int, playerName string) (*Room, error)
 	room.DrawCard(playerName)
 
 	rooms[roomCode] = room
-	return room, nil
+	roomsCreatedTotal.Inc()
+	return room, playerName, nil
 }
 
 // JoinRoom adds a player to an existing room and deals them cards
 func JoinRoom(roomCode, playerName string) (cardsDealt int, err error) {
+	return JoinRoomWithPassword(roomCode, playerName, "")
+}
+
+// JoinRoomWithPassword is JoinRoom for rooms that may be password protected
+func JoinRoomWithPassword(roomCode, playerName, password string) (cardsDealt int, err error) {
+	playerName = normalizePlayerName(playerName)
+	if err := validatePlayerName(playerName); err != nil {
+		return 0, err
+	}
+
 	room, exists := getRoom(roomCode)
 	if !exists {
 		return 0, ErrRoomNotFound
 	}
 
-	room.mu.Lock()
-	defer room.mu.Unlock()
+	// The password never changes after creation, so check it before locking
+	if room.password != nil && !room.password.Matches(password) {
+		return 0, ErrWrongPassword
+	}
+
+	return room.join(playerName)
+}
+
+// join seats a player whose name and right to enter have already been
+// checked
+func (r *Room) join(playerName string) (cardsDealt int, err error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
 
-	if room.HasPlayer(playerName) {
+	if r.HasPlayer(playerName) {
 		return 0, ErrPlayerExists
 	}
 
-	room.Players = append(room.Players, playerName)
-	room.PlayerHands[playerName] = []Card{}
+	if r.MaxPlayers > 0 && len(r.Players) >= r.MaxPlayers {
+		return 0, ErrRoomFull
+	}
+
+	r.Players = append(r.Players, playerName)
+	r.PlayerHands[playerName] = []Card{}
 
 	// Deal cards based on player count (including new player)
 	// 2 cards if 3 or fewer players, 1 card if 4 or more
-	cardsToAdd := room.GetCardsPerPlayer()
-	for i := 0; i < cardsToAdd; i++ {
-		if room.DrawCard(playerName) {
+	if !r.GameOver {
+		for r.DrawCard(playerName) {
 			cardsDealt++
 		}
 	}
 
+	notifyRoom(r.RoomCode)
 	return cardsDealt, nil
 }
 
@@ -420,9 +552,11 @@ func LeaveRoom(roomCode, playerName string) error {
 	room.mu.Lock()
 	defer room.mu.Unlock()
 
-	if !room.HasPlayer(playerName) {
+	player, ok := room.findPlayer(playerName)
+	if !ok {
 		return ErrPlayerNotFound
 	}
+	playerName = player
 
 	// Remove player from players list
 	for i, player := range room.Players {
@@ -432,12 +566,15 @@ func LeaveRoom(roomCode, playerName string) error {
 		}
 	}
 
-	// Return player's cards to the deck
+	// Return player's cards to the deck, where the remaining players pick
+	// them up
 	if cards, exists := room.PlayerHands[playerName]; exists {
 		room.CardDeck = append(room.CardDeck, cards...)
 		delete(room.PlayerHands, playerName)
 	}
+	room.topUpHands()
 
+	notifyRoom(room.RoomCode)
 	return nil
 }
 
@@ -457,7 +594,7 @@ func StartGame(roomCode string) error {
 
 	// Reset the game state for a new game
 	// Shuffle new words
-	words := shuffleWords()
+	words := shuffleWords(room.WordPack)
 	for i := 0; i < room.GridSize; i++ {
 		room.RowWords[i] = words[i]
 		room.ColumnWords[i] = words[i+room.GridSize]
@@ -487,7 +624,10 @@ func StartGame(roomCode string) error {
 
 	room.GameStarted = true
 	room.GameOver = false
+	room.StartedAt = time.Now()
+	gamesStartedTotal.Inc()
 
+	notifyRoom(room.RoomCode)
 	return nil
 }
 
@@ -509,9 +649,11 @@ func SubmitGuess(roomCode, playerName string, row, col int, correct bool) (gameO
 		return false, ErrGameOver
 	}
 
-	if !room.HasPlayer(playerName) {
+	player, ok := room.findPlayer(playerName)
+	if !ok {
 		return false, ErrPlayerNotFound
 	}
+	playerName = player
 
 	if !room.HasCard(playerName, row, col) {
 		return false, ErrNoCard
@@ -523,8 +665,10 @@ func SubmitGuess(roomCode, playerName string, row, col int, correct bool) (gameO
 	// Update grid
 	if correct {
 		room.Grid[row][col].GuessedCorrectly = true
+		guessesTotal.Inc("correct")
 	} else {
 		room.Grid[row][col].DiscardedBy = playerName
+		guessesTotal.Inc("discarded")
 	}
 
 	// Draw a new card
@@ -532,7 +676,12 @@ func SubmitGuess(roomCode, playerName string, row, col int, correct bool) (gameO
 
 	// Check if game is over
 	room.GameOver = room.CheckGameOver()
+	if room.GameOver {
+		room.recordGame(time.Now())
+		gamesFinishedTotal.Inc()
+	}
 
+	notifyRoom(room.RoomCode)
 	return room.GameOver, nil
 }
 
@@ -546,16 +695,22 @@ func GetGameState(roomCode, playerName string) (*GameStateResponse, error) {
 	room.mu.RLock()
 	defer room.mu.RUnlock()
 
-	if !room.HasPlayer(playerName) {
+	player, ok := room.findPlayer(playerName)
+	if !ok {
 		return nil, ErrPlayerNotFound
 	}
+	playerName = player
 
 	// Build grid response (player-specific view)
 	gridResponse := make([][]CellResponse, room.GridSize)
+	correctGuesses := 0
 	for row := 0; row < room.GridSize; row++ {
 		gridResponse[row] = make([]CellResponse, room.GridSize)
 		for col := 0; col < room.GridSize; col++ {
 			cell := room.Grid[row][col]
+			if cell.GuessedCorrectly {
+				correctGuesses++
+			}
 			gridResponse[row][col] = CellResponse{
 				GuessedCorrectly: cell.GuessedCorrectly,
 				DiscardedByMe:    cell.DiscardedBy == playerName,
@@ -570,20 +725,62 @@ func GetGameState(roomCode, playerName string) (*GameStateResponse, error) {
 	}
 
 	return &GameStateResponse{
-		RoomCode:    room.RoomCode,
-		GridSize:    room.GridSize,
-		GameStarted: room.GameStarted,
-		GameOver:    room.GameOver,
-		RowWords:    room.RowWords,
-		ColumnWords: room.ColumnWords,
-		PlayerCards: playerCards,
-		Grid:        gridResponse,
-		Players:     room.Players,
+		RoomCode:       room.RoomCode,
+		GridSize:       room.GridSize,
+		GameStarted:    room.GameStarted,
+		GameOver:       room.GameOver,
+		CorrectGuesses: correctGuesses,
+		TotalCells:     room.GridSize * room.GridSize,
+		RowWords:       room.RowWords,
+		ColumnWords:    room.ColumnWords,
+		PlayerCards:    playerCards,
+		Grid:           gridResponse,
+		Players:        room.Players,
 	}, nil
 }
 
-// ClearRooms clears all rooms (useful for testing)
+// EndGame forces a started game in a room to be over
+func EndGame(roomCode string) error {
+	room, exists := getRoom(roomCode)
+	if !exists {
+		return ErrRoomNotFound
+	}
+
+	room.mu.Lock()
+	defer room.mu.Unlock()
+
+	if !room.GameStarted {
+		return ErrGameNotStarted
+	}
+
+	if !room.GameOver {
+		room.recordGame(time.Now())
+	}
+	room.GameOver = true
+	notifyRoom(room.RoomCode)
+	return nil
+}
+
+// DeleteRoom removes a room from the registry
+func DeleteRoom(roomCode string) error {
+	roomCode = normalizeRoomCode(roomCode)
+
+	roomsMu.Lock()
+	defer roomsMu.Unlock()
+
+	if _, exists := rooms[roomCode]; !exists {
+		return ErrRoomNotFound
+	}
+	delete(rooms, roomCode)
+	notifyRoom(roomCode)
+	return nil
+}
+
+// ClearRooms clears all rooms (useful for testing). Bots are stopped first,
+// so that none is still playing once the next rooms are created.
 func ClearRooms() {
+	stopAllBots()
+
 	roomsMu.Lock()
 	defer roomsMu.Unlock()
 	rooms = make(map[string]*Room)
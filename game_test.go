@@ -1,6 +1,7 @@
 package main
 
 import (
+	"errors"
 	"testing"
 )
 
@@ -73,7 +74,17 @@ func TestCreateRoomDifferentGridSizes(t *testing.T) {
 		t.Errorf("Expected 9 total cards (3x3), got %d", len(room3.CardDeck)+len(room3.PlayerHands["Player1"]))
 	}
 
-	// Test with grid size 7
+	// Sizes outside the configured range are refused
+	if _, err := CreateRoom("GRID2", 2, "Player2"); !errors.Is(err, ErrInvalidGridSize) {
+		t.Errorf("Expected ErrInvalidGridSize for grid size 2, got %v", err)
+	}
+	if _, err := CreateRoom("GRID7", 7, "Player2"); !errors.Is(err, ErrInvalidGridSize) {
+		t.Errorf("Expected ErrInvalidGridSize for grid size 7, got %v", err)
+	}
+
+	// Test with grid size 7, once the server allows it
+	defer func(max int) { config.MaxGridSize = max }(config.MaxGridSize)
+	config.MaxGridSize = 7
 	room7, err := CreateRoom("GRID7", 7, "Player2")
 	if err != nil {
 		t.Fatalf("Expected no error, got %v", err)
@@ -154,6 +165,62 @@ func TestJoinRoomCardLimit(t *testing.T) {
 	if cardsDealt != 1 {
 		t.Errorf("Expected 1 card dealt for 5th player, got %d", cardsDealt)
 	}
+
+	// Players dealt 2 cards before the 4th joined keep them
+	room, _ := getRoom("CARDLIMIT")
+	for _, player := range []string{"P1", "P2", "P3"} {
+		if len(room.PlayerHands[player]) != 2 {
+			t.Errorf("Expected %s to keep 2 cards, got %d", player, len(room.PlayerHands[player]))
+		}
+	}
+}
+
+func TestJoinMidGameKeepsHands(t *testing.T) {
+	ClearRooms()
+
+	CreateRoom("MIDJOIN", 3, "P1")
+	JoinRoom("MIDJOIN", "P2")
+	StartGame("MIDJOIN")
+	JoinRoom("MIDJOIN", "P3")
+	JoinRoom("MIDJOIN", "P4")
+
+	// P1 plays one of the 2 cards dealt before the limit dropped to 1, and
+	// draws no replacement
+	room, _ := getRoom("MIDJOIN")
+	card := room.PlayerHands["P1"][0]
+	if _, err := SubmitGuess("MIDJOIN", "P1", card.Row, card.Column, true); err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	if got := len(room.PlayerHands["P1"]); got != 1 {
+		t.Errorf("Expected P1 to hold 1 card, got %d", got)
+	}
+	if got := len(room.PlayerHands["P2"]); got != 2 {
+		t.Errorf("Expected P2 to keep 2 cards, got %d", got)
+	}
+}
+
+func TestLeaveRoomRedealsCards(t *testing.T) {
+	ClearRooms()
+
+	CreateRoom("REDEAL", 3, "P1")
+	JoinRoom("REDEAL", "P2")
+	JoinRoom("REDEAL", "P3")
+	JoinRoom("REDEAL", "P4")
+	StartGame("REDEAL")
+
+	// Back to 3 players, so everyone holds 2 cards again
+	if err := LeaveRoom("REDEAL", "P4"); err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	room, _ := getRoom("REDEAL")
+	for _, player := range room.Players {
+		if len(room.PlayerHands[player]) != 2 {
+			t.Errorf("Expected %s to hold 2 cards, got %d", player, len(room.PlayerHands[player]))
+		}
+	}
+	if len(room.CardDeck) != 3 {
+		t.Errorf("Expected 3 cards left in the deck, got %d", len(room.CardDeck))
+	}
 }
 
 func TestStartGame(t *testing.T) {
@@ -600,3 +667,117 @@ func TestDrawCardWith4Players(t *testing.T) {
 		t.Errorf("Expected P1 to still have 1 card, got %d", len(room.PlayerHands["P1"]))
 	}
 }
+
+func TestEndGame(t *testing.T) {
+	ClearRooms()
+
+	CreateRoom("ENDTEST", 3, "Alice")
+	JoinRoom("ENDTEST", "Bob")
+
+	// Can't end a game that hasn't started
+	err := EndGame("ENDTEST")
+	if err != ErrGameNotStarted {
+		t.Errorf("Expected ErrGameNotStarted, got %v", err)
+	}
+
+	StartGame("ENDTEST")
+	if err := EndGame("ENDTEST"); err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+
+	room, _ := getRoom("ENDTEST")
+	if !room.GameOver {
+		t.Error("Expected GameOver to be true")
+	}
+
+	// Nobody is dealt cards in a finished game
+	if cardsDealt, _ := JoinRoom("ENDTEST", "Carol"); cardsDealt != 0 {
+		t.Errorf("Expected no cards dealt after the game ended, got %d", cardsDealt)
+	}
+
+	if err := EndGame("NONEXISTENT"); err != ErrRoomNotFound {
+		t.Errorf("Expected ErrRoomNotFound, got %v", err)
+	}
+}
+
+func TestDeleteRoom(t *testing.T) {
+	ClearRooms()
+
+	CreateRoom("DELETETEST", 5, "Alice")
+	if err := DeleteRoom("DELETETEST"); err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	if _, exists := getRoom("DELETETEST"); exists {
+		t.Error("Expected room to be deleted")
+	}
+	if err := DeleteRoom("DELETETEST"); err != ErrRoomNotFound {
+		t.Errorf("Expected ErrRoomNotFound, got %v", err)
+	}
+}
+
+func TestGetRoomDetail(t *testing.T) {
+	ClearRooms()
+
+	CreateRoom("DETAILTEST", 3, "Alice")
+	JoinRoom("DETAILTEST", "Bob")
+	StartGame("DETAILTEST")
+
+	room, _ := getRoom("DETAILTEST")
+	aliceCard := room.PlayerHands["Alice"][0]
+	SubmitGuess("DETAILTEST", "Alice", aliceCard.Row, aliceCard.Column, false)
+
+	detail, err := GetRoomDetail("DETAILTEST")
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	if detail.Grid[aliceCard.Row][aliceCard.Column].DiscardedBy != "Alice" {
+		t.Error("Expected detail to show the cell discarded by Alice")
+	}
+
+	// Every card is either in the deck, in a hand, or resolved on the grid
+	total := len(detail.CardDeck) + 1
+	for _, hand := range detail.PlayerHands {
+		total += len(hand)
+	}
+	if total != 9 {
+		t.Errorf("Expected 9 cards accounted for, got %d", total)
+	}
+
+	summaries := ListRoomSummaries()
+	if len(summaries) != 1 || summaries[0].RoomCode != "DETAILTEST" {
+		t.Errorf("Expected one summary for DETAILTEST, got %v", summaries)
+	}
+}
+
+func TestPrivateRoom(t *testing.T) {
+	ClearRooms()
+
+	_, _, err := CreateRoomWithOptions("PRIVATE", "Alice", RoomOptions{GridSize: 5, Password: "abc"})
+	if err != ErrInvalidPassword {
+		t.Errorf("Expected ErrInvalidPassword for a short password, got %v", err)
+	}
+
+	room, _, err := CreateRoomWithOptions("PRIVATE", "Alice", RoomOptions{GridSize: 5, Password: "1234"})
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	if !room.IsPrivate() {
+		t.Error("Expected room to be private")
+	}
+
+	// Missing and wrong passwords are rejected
+	if _, err := JoinRoom("PRIVATE", "Bob"); err != ErrWrongPassword {
+		t.Errorf("Expected ErrWrongPassword without a password, got %v", err)
+	}
+	if _, err := JoinRoomWithPassword("PRIVATE", "Bob", "4321"); err != ErrWrongPassword {
+		t.Errorf("Expected ErrWrongPassword, got %v", err)
+	}
+	if room.HasPlayer("Bob") {
+		t.Error("Expected Bob not to be added after a wrong password")
+	}
+
+	// Correct password joins
+	if _, err := JoinRoomWithPassword("PRIVATE", "Bob", "1234"); err != nil {
+		t.Errorf("Expected no error with the right password, got %v", err)
+	}
+}
@@ -1,3 +1,5 @@
 module github.com/dfturn/crossclues2
 
 go 1.22
+
+require golang.org/x/text v0.21.0
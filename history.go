@@ -0,0 +1,203 @@
+package main
+
+import (
+	"encoding/csv"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/dfturn/crossclues2/internal/board"
+)
+
+// Finished games are recorded on their room so that they can be looked back
+// on after the next game starts. Like rooms themselves, records are kept in
+// memory only and go away with the room.
+
+// maxRoomHistory bounds how many finished games a room keeps; older games
+// are dropped first
+const maxRoomHistory = 100
+
+// Cell outcomes in a GameRecord
+const (
+	outcomeGuessed    = "guessed"
+	outcomeDiscarded  = "discarded"
+	outcomeUnresolved = "unresolved"
+)
+
+// recordGame adds the current game to the room's history. Callers must hold
+// r.mu for writing.
+func (r *Room) recordGame(endedAt time.Time) {
+	record := GameRecord{
+		RoomCode:    r.RoomCode,
+		GridSize:    r.GridSize,
+		WordPack:    r.WordPack,
+		Players:     append([]string{}, r.Players...),
+		RowWords:    append([]string{}, r.RowWords...),
+		ColumnWords: append([]string{}, r.ColumnWords...),
+		Cells:       make([]CellRecord, 0, r.GridSize*r.GridSize),
+		TotalCells:  r.GridSize * r.GridSize,
+		StartedAt:   r.StartedAt,
+		EndedAt:     endedAt,
+	}
+	for row := 0; row < r.GridSize; row++ {
+		for col := 0; col < r.GridSize; col++ {
+			cell := r.Grid[row][col]
+			outcome := outcomeUnresolved
+			switch {
+			case cell.GuessedCorrectly:
+				outcome = outcomeGuessed
+				record.Score++
+			case cell.DiscardedBy != "":
+				outcome = outcomeDiscarded
+			default:
+				record.EndedEarly = true
+			}
+			record.Cells = append(record.Cells, CellRecord{
+				Row:         row,
+				Column:      col,
+				RowWord:     r.RowWords[row],
+				ColumnWord:  r.ColumnWords[col],
+				Outcome:     outcome,
+				DiscardedBy: cell.DiscardedBy,
+			})
+		}
+	}
+
+	r.gamesPlayed++
+	record.Game = r.gamesPlayed
+	r.History = append(r.History, record)
+	if len(r.History) > maxRoomHistory {
+		r.History = append([]GameRecord(nil), r.History[len(r.History)-maxRoomHistory:]...)
+	}
+}
+
+// GetHistory returns the finished games of a room, oldest first.
+// playerName must be seated in the room, and password is needed if the room
+// is private.
+func GetHistory(roomCode, playerName, password string) (*GameHistoryResponse, error) {
+	room, exists := getRoom(roomCode)
+	if !exists {
+		return nil, ErrRoomNotFound
+	}
+	if err := checkSeated(room, playerName, password); err != nil {
+		return nil, err
+	}
+
+	room.mu.RLock()
+	defer room.mu.RUnlock()
+
+	// Records are never changed once added, so they can be shared
+	return &GameHistoryResponse{
+		RoomCode: room.RoomCode,
+		Games:    append([]GameRecord{}, room.History...),
+	}, nil
+}
+
+// historyCSVHeader names the columns of the CSV export, one row per cell
+var historyCSVHeader = []string{
+	"game", "room_code", "started_at", "ended_at", "grid_size", "word_pack", "players", "score", "total_cells",
+	"cell", "row", "column", "row_word", "column_word", "outcome", "discarded_by",
+}
+
+// csvFormulaPrefixes start the fields that spreadsheets evaluate as formulas
+const csvFormulaPrefixes = "=+-@\t\r"
+
+// csvSafe stops a spreadsheet from running a field, such as a player name,
+// as a formula by prefixing it with a single quote
+func csvSafe(field string) string {
+	if field != "" && strings.IndexByte(csvFormulaPrefixes, field[0]) >= 0 {
+		return "'" + field
+	}
+	return field
+}
+
+// writeHistoryCSV writes a room's history with one row per cell of each
+// game. Rows and columns are numbered from 0, as in the JSON; cell is the
+// label the web client shows, e.g. B3. Fields that would be read as
+// formulas are escaped with csvSafe.
+func writeHistoryCSV(w io.Writer, history *GameHistoryResponse) error {
+	out := csv.NewWriter(w)
+	out.Write(historyCSVHeader)
+	for _, game := range history.Games {
+		players := strings.Join(game.Players, ";")
+		for _, cell := range game.Cells {
+			row := []string{
+				strconv.Itoa(game.Game),
+				game.RoomCode,
+				game.StartedAt.UTC().Format(time.RFC3339),
+				game.EndedAt.UTC().Format(time.RFC3339),
+				strconv.Itoa(game.GridSize),
+				game.WordPack,
+				players,
+				strconv.Itoa(game.Score),
+				strconv.Itoa(game.TotalCells),
+				board.Label(cell.Row, cell.Column),
+				strconv.Itoa(cell.Row),
+				strconv.Itoa(cell.Column),
+				cell.RowWord,
+				cell.ColumnWord,
+				cell.Outcome,
+				cell.DiscardedBy,
+			}
+			for i, field := range row {
+				row[i] = csvSafe(field)
+			}
+			out.Write(row)
+		}
+	}
+	out.Flush()
+	return out.Error()
+}
+
+// HTTP Handlers
+
+// handleGetHistory returns a room's finished games as JSON, or as a
+// download with format=json or format=csv
+func handleGetHistory(w http.ResponseWriter, r *http.Request) {
+	roomCode := normalizeRoomCode(r.PathValue("code"))
+
+	query := r.URL.Query()
+	playerName := query.Get("playerName")
+	if playerName == "" {
+		writeError(w, ErrPlayerNameRequired)
+		return
+	}
+	logPlayer(r, playerName)
+
+	format := query.Get("format")
+	if format != "" && format != "json" && format != "csv" {
+		writeError(w, ErrInvalidFormat)
+		return
+	}
+
+	// Wrong passwords count against the same limit as joins
+	attemptKey := roomCode + "|" + clientIP(r)
+	if blocked, wait := passwordLimiter.Blocked(attemptKey, time.Now()); blocked {
+		writeRateLimited(w, wait)
+		return
+	}
+
+	history, err := GetHistory(roomCode, playerName, query.Get("password"))
+	if err != nil {
+		if errors.Is(err, ErrWrongPassword) {
+			passwordLimiter.Allow(attemptKey, time.Now())
+		}
+		writeError(w, err)
+		return
+	}
+
+	if format != "" {
+		filename := fmt.Sprintf("crossclues-%s-history.%s", history.RoomCode, format)
+		w.Header().Set("Content-Disposition", fmt.Sprintf("attachment; filename=%q", filename))
+	}
+	if format == "csv" {
+		w.Header().Set("Content-Type", "text/csv; charset=utf-8")
+		writeHistoryCSV(w, history)
+		return
+	}
+	writeJSON(w, http.StatusOK, history)
+}
@@ -0,0 +1,204 @@
+package main
+
+import (
+	"encoding/csv"
+	"encoding/json"
+	"net/http"
+	"testing"
+)
+
+// finishGame resolves every card in play, discarding Bob's and guessing the
+// rest
+func finishGame(t *testing.T, roomCode string) {
+	t.Helper()
+	for {
+		state, _ := GetGameState(roomCode, "Alice")
+		if state.GameOver {
+			return
+		}
+		played := false
+		for _, player := range state.Players {
+			s, _ := GetGameState(roomCode, player)
+			for _, card := range s.PlayerCards {
+				if _, err := SubmitGuess(roomCode, player, card.Row, card.Column, player != "Bob"); err != nil {
+					t.Fatal(err)
+				}
+				played = true
+			}
+		}
+		if !played {
+			t.Fatal("Nobody holds a card but the game is not over")
+		}
+	}
+}
+
+func TestGameHistory(t *testing.T) {
+	ClearRooms()
+	CreateRoom("HISTORY", 3, "Alice")
+	JoinRoom("HISTORY", "Bob")
+
+	if history, _ := GetHistory("HISTORY", "Alice", ""); len(history.Games) != 0 {
+		t.Fatalf("Expected no history before a game, got %v", history.Games)
+	}
+
+	StartGame("HISTORY")
+	finishGame(t, "HISTORY")
+
+	history, err := GetHistory("history", "Alice", "")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(history.Games) != 1 {
+		t.Fatalf("Expected 1 game, got %d", len(history.Games))
+	}
+	game := history.Games[0]
+	if game.Game != 1 || game.TotalCells != 9 || len(game.Cells) != 9 || game.EndedEarly {
+		t.Errorf("Unexpected record %+v", game)
+	}
+	if game.StartedAt.IsZero() || game.EndedAt.Before(game.StartedAt) {
+		t.Errorf("Expected the game to end after it started, got %v to %v", game.StartedAt, game.EndedAt)
+	}
+	guessed := 0
+	for _, cell := range game.Cells {
+		switch cell.Outcome {
+		case outcomeGuessed:
+			guessed++
+		case outcomeDiscarded:
+			if cell.DiscardedBy != "Bob" {
+				t.Errorf("Expected Bob to have discarded %+v", cell)
+			}
+		default:
+			t.Errorf("Expected every cell to be resolved, got %+v", cell)
+		}
+		if cell.RowWord != game.RowWords[cell.Row] || cell.ColumnWord != game.ColumnWords[cell.Column] {
+			t.Errorf("Cell %+v does not match the board words", cell)
+		}
+	}
+	if guessed != game.Score {
+		t.Errorf("Expected a score of %d, got %d", guessed, game.Score)
+	}
+
+	// The next game starts afresh; the record keeps the old board
+	room, _ := getRoom("HISTORY")
+	oldWords := append([]string(nil), room.RowWords...)
+	StartGame("HISTORY")
+	room.RowWords[0] = "CHANGED"
+	if history, _ := GetHistory("HISTORY", "Alice", ""); history.Games[0].RowWords[0] != oldWords[0] {
+		t.Error("Expected the record to keep its own copy of the words")
+	}
+
+	// An ended game is recorded once, as ended early
+	EndGame("HISTORY")
+	EndGame("HISTORY")
+	history, _ = GetHistory("HISTORY", "Alice", "")
+	if len(history.Games) != 2 {
+		t.Fatalf("Expected 2 games, got %d", len(history.Games))
+	}
+	if game := history.Games[1]; game.Game != 2 || !game.EndedEarly || game.Score != 0 || game.Cells[0].Outcome != outcomeUnresolved {
+		t.Errorf("Unexpected record for an ended game %+v", game)
+	}
+
+	if _, err := GetHistory("NOPE", "Alice", ""); err != ErrRoomNotFound {
+		t.Errorf("Expected ErrRoomNotFound, got %v", err)
+	}
+}
+
+func TestGameHistoryLimit(t *testing.T) {
+	ClearRooms()
+	room, _ := CreateRoom("LIMIT", 3, "Alice")
+	room.mu.Lock()
+	for i := 0; i < maxRoomHistory+5; i++ {
+		room.recordGame(room.CreatedAt)
+	}
+	room.mu.Unlock()
+
+	history, _ := GetHistory("LIMIT", "Alice", "")
+	if len(history.Games) != maxRoomHistory || history.Games[0].Game != 6 {
+		t.Errorf("Expected the last %d games from game 6, got %d from game %d", maxRoomHistory, len(history.Games), history.Games[0].Game)
+	}
+}
+
+func TestHistoryHandler(t *testing.T) {
+	ClearRooms()
+	CreateRoom("EXPORT", 3, "Alice")
+	JoinRoom("EXPORT", "Bob")
+	StartGame("EXPORT")
+	finishGame(t, "EXPORT")
+	mux := newRouter()
+
+	rec := serve(mux, http.MethodGet, "/api/v1/rooms/export/history?playerName=Alice", "")
+	if rec.Code != http.StatusOK || rec.Header().Get("Content-Disposition") != "" {
+		t.Fatalf("Expected 200 without an attachment, got %d: %s", rec.Code, rec.Body)
+	}
+	var history GameHistoryResponse
+	json.NewDecoder(rec.Body).Decode(&history)
+	if history.RoomCode != "EXPORT" || len(history.Games) != 1 {
+		t.Fatalf("Unexpected history %+v", history)
+	}
+
+	rec = serve(mux, http.MethodGet, "/api/v1/rooms/EXPORT/history?playerName=Alice&format=json", "")
+	if got := rec.Header().Get("Content-Disposition"); got != `attachment; filename="crossclues-EXPORT-history.json"` {
+		t.Errorf("Expected a JSON attachment, got %q", got)
+	}
+
+	rec = serve(mux, http.MethodGet, "/api/rooms/EXPORT/history?playerName=Alice&format=csv", "")
+	if ct := rec.Header().Get("Content-Type"); rec.Code != http.StatusOK || ct != "text/csv; charset=utf-8" {
+		t.Fatalf("Expected a CSV, got %d %q", rec.Code, ct)
+	}
+	rows, err := csv.NewReader(rec.Body).ReadAll()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(rows) != 10 || len(rows[0]) != len(historyCSVHeader) {
+		t.Fatalf("Expected a header and 9 cells, got %d rows", len(rows))
+	}
+	first := history.Games[0].Cells[0]
+	if rows[1][9] != "A1" || rows[1][12] != first.RowWord || rows[1][14] != first.Outcome || rows[1][15] != first.DiscardedBy {
+		t.Errorf("Unexpected first row %v for %+v", rows[1], first)
+	}
+	if rows[1][6] != "Alice;Bob" {
+		t.Errorf("Expected the players joined by semicolons, got %q", rows[1][6])
+	}
+
+	// Names a spreadsheet would run as formulas are escaped
+	CreateRoom("FORMULA", 3, "=HYPERLINK(\"x\")")
+	JoinRoom("FORMULA", "Alice")
+	JoinRoom("FORMULA", "Bob")
+	StartGame("FORMULA")
+	finishGame(t, "FORMULA")
+	rec = serve(mux, http.MethodGet, "/api/v1/rooms/FORMULA/history?playerName=Alice&format=csv", "")
+	rows, _ = csv.NewReader(rec.Body).ReadAll()
+	if len(rows) < 2 || rows[1][6] != `'=HYPERLINK("x");Alice;Bob` {
+		t.Errorf("Expected the players to be escaped, got %v", rows)
+	}
+	for field, want := range map[string]string{
+		"+1": "'+1", "-1": "'-1", "@SUM(A1)": "'@SUM(A1)", "\tx": "'\tx", "\rx": "'\rx", "Bob=1": "Bob=1", "": "",
+	} {
+		if got := csvSafe(field); got != want {
+			t.Errorf("csvSafe(%q) = %q, want %q", field, got, want)
+		}
+	}
+
+	rec = serve(mux, http.MethodGet, "/api/v1/rooms/EXPORT/history?playerName=Alice&format=xml", "")
+	if rec.Code != http.StatusBadRequest {
+		t.Errorf("Expected 400 for an unknown format, got %d", rec.Code)
+	}
+	rec = serve(mux, http.MethodGet, "/api/v1/rooms/NOPE/history?playerName=Alice", "")
+	if rec.Code != http.StatusNotFound {
+		t.Errorf("Expected 404 for an unknown room, got %d", rec.Code)
+	}
+
+	// Only seated players may read it, with the password for a private room
+	CreateRoomWithOptions("HUSHED", "Alice", RoomOptions{GridSize: 3, Password: "sesame"})
+	for path, want := range map[string]int{
+		"/api/v1/rooms/EXPORT/history":                                  http.StatusBadRequest,
+		"/api/v1/rooms/EXPORT/history?playerName=Mallory":               http.StatusNotFound,
+		"/api/v1/rooms/HUSHED/history?playerName=Alice":                 http.StatusForbidden,
+		"/api/v1/rooms/HUSHED/history?playerName=Alice&password=wrong":  http.StatusForbidden,
+		"/api/v1/rooms/HUSHED/history?playerName=Alice&password=sesame": http.StatusOK,
+	} {
+		if rec := serve(mux, http.MethodGet, path, ""); rec.Code != want {
+			t.Errorf("%s: expected %d, got %d", path, want, rec.Code)
+		}
+	}
+}
@@ -0,0 +1,107 @@
+// Package board lays out a CrossClues grid as plain text. It is shared by
+// the line protocol and the terminal client so that both name cells and
+// draw the board the same way.
+package board
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+	"unicode/utf8"
+)
+
+// MaxSize is the largest grid Label can name, one row per letter A-Z
+const MaxSize = 26
+
+// Label names a cell with a row letter and 1-based column number, as the
+// web client does, e.g. "B3"
+func Label(row, col int) string {
+	return fmt.Sprintf("%c%d", 'A'+row, col+1)
+}
+
+// ParseLabel is the inverse of Label. It accepts either case.
+func ParseLabel(label string, gridSize int) (row, col int, ok bool) {
+	label = strings.ToUpper(strings.TrimSpace(label))
+	if len(label) < 2 {
+		return 0, 0, false
+	}
+	row = int(label[0]) - 'A'
+	n, err := strconv.Atoi(label[1:])
+	if err != nil || row < 0 || row >= gridSize || n < 1 || n > gridSize {
+		return 0, 0, false
+	}
+	return row, n - 1, true
+}
+
+// Cell is what a player sees at one cell of the grid
+type Cell int
+
+const (
+	Open Cell = iota
+	Guessed
+	Discarded
+	Held
+)
+
+// Marks are the symbols drawn for each kind of cell. Held cells show their
+// label instead.
+type Marks struct {
+	Guessed   string
+	Discarded string
+	Open      string
+}
+
+// Lines lays out the grid: a line of column numbers, a line of column
+// words, then one line per row with its letter, its word and a mark for
+// each cell. Trailing spaces are trimmed.
+func Lines(rowWords, columnWords []string, marks Marks, cell func(row, col int) Cell) []string {
+	rowWidth := 0
+	for _, word := range rowWords {
+		rowWidth = max(rowWidth, utf8.RuneCountInString(word))
+	}
+	rowWidth += 3 // "A " prefix and a space
+	colWidth := 4
+	for _, word := range columnWords {
+		colWidth = max(colWidth, utf8.RuneCountInString(word)+2)
+	}
+
+	var numbers, words strings.Builder
+	numbers.WriteString(pad("", rowWidth))
+	words.WriteString(pad("", rowWidth))
+	for col, word := range columnWords {
+		numbers.WriteString(pad(strconv.Itoa(col+1), colWidth))
+		words.WriteString(pad(word, colWidth))
+	}
+	lines := []string{
+		strings.TrimRight(numbers.String(), " "),
+		strings.TrimRight(words.String(), " "),
+	}
+
+	for row, word := range rowWords {
+		var b strings.Builder
+		b.WriteString(pad(fmt.Sprintf("%c %s", 'A'+row, word), rowWidth))
+		for col := range columnWords {
+			mark := marks.Open
+			switch cell(row, col) {
+			case Guessed:
+				mark = marks.Guessed
+			case Discarded:
+				mark = marks.Discarded
+			case Held:
+				mark = Label(row, col)
+			}
+			b.WriteString(pad(mark, colWidth))
+		}
+		lines = append(lines, strings.TrimRight(b.String(), " "))
+	}
+	return lines
+}
+
+// pad left-aligns s in a field of width runes, keeping at least one space
+// after it
+func pad(s string, width int) string {
+	if n := utf8.RuneCountInString(s); n < width {
+		return s + strings.Repeat(" ", width-n)
+	}
+	return s + " "
+}
@@ -0,0 +1,46 @@
+package board
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestLabel(t *testing.T) {
+	for row := 0; row < 5; row++ {
+		for col := 0; col < 5; col++ {
+			r, c, ok := ParseLabel(Label(row, col), 5)
+			if !ok || r != row || c != col {
+				t.Errorf("%s: expected %d,%d, got %d,%d (%v)", Label(row, col), row, col, r, c, ok)
+			}
+		}
+	}
+	if got := Label(1, 2); got != "B3" {
+		t.Errorf("Expected B3, got %s", got)
+	}
+	if row, col, ok := ParseLabel(" c1 ", 3); !ok || row != 2 || col != 0 {
+		t.Errorf("Expected C1 to parse case-insensitively, got %d,%d (%v)", row, col, ok)
+	}
+	for _, label := range []string{"", "B", "3B", "D1", "A0", "A4", "AA"} {
+		if _, _, ok := ParseLabel(label, 3); ok {
+			t.Errorf("Expected %q not to parse", label)
+		}
+	}
+}
+
+func TestLines(t *testing.T) {
+	cells := [][]Cell{
+		{Guessed, Open},
+		{Held, Discarded},
+	}
+	got := Lines([]string{"APPLE", "SEA"}, []string{"RIVER", "ICE"}, Marks{Guessed: "#", Discarded: "x", Open: "."},
+		func(row, col int) Cell { return cells[row][col] })
+	want := []string{
+		"        1      2",
+		"        RIVER  ICE",
+		"A APPLE #      .",
+		"B SEA   B1     x",
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("Unexpected grid:\n%q\nwant:\n%q", got, want)
+	}
+}
@@ -0,0 +1,96 @@
+package main
+
+import (
+	"context"
+	"log/slog"
+	"net/http"
+	"sync"
+	"sync/atomic"
+	"time"
+)
+
+// Server lifecycle: health checks, readiness and shutdown hooks
+
+var (
+	shuttingDown atomic.Bool
+	shutdownCh   = make(chan struct{})
+	shutdownOnce sync.Once
+
+	shutdownHooksMu sync.Mutex
+	shutdownHooks   []shutdownHook
+)
+
+type shutdownHook struct {
+	name string
+	fn   func(ctx context.Context) error
+}
+
+// RegisterShutdownHook registers fn to run once open requests have drained
+// during a graceful shutdown. Hooks run in registration order.
+func RegisterShutdownHook(name string, fn func(ctx context.Context) error) {
+	shutdownHooksMu.Lock()
+	defer shutdownHooksMu.Unlock()
+	shutdownHooks = append(shutdownHooks, shutdownHook{name: name, fn: fn})
+}
+
+// beginShutdown marks the server as shutting down. New rooms are refused,
+// /readyz starts failing and long-lived handlers watching shutdownStarted
+// return so the server can drain.
+func beginShutdown() {
+	shutdownOnce.Do(func() {
+		shuttingDown.Store(true)
+		close(shutdownCh)
+	})
+}
+
+// isShuttingDown reports whether a graceful shutdown has begun
+func isShuttingDown() bool {
+	return shuttingDown.Load()
+}
+
+// shutdownStarted returns a channel that is closed when shutdown begins
+func shutdownStarted() <-chan struct{} {
+	return shutdownCh
+}
+
+// drain shuts srv down gracefully: new rooms are refused and /readyz fails
+// at once, open requests get up to timeout to finish, and the shutdown hooks
+// run after them within what is left of it
+func drain(srv *http.Server, timeout time.Duration) {
+	beginShutdown()
+
+	ctx, cancel := context.WithTimeout(context.Background(), timeout)
+	defer cancel()
+	if err := srv.Shutdown(ctx); err != nil {
+		slog.Warn("Graceful shutdown incomplete", "err", err)
+	}
+	runShutdownHooks(ctx)
+}
+
+// runShutdownHooks runs every registered hook, logging failures
+func runShutdownHooks(ctx context.Context) {
+	shutdownHooksMu.Lock()
+	hooks := make([]shutdownHook, len(shutdownHooks))
+	copy(hooks, shutdownHooks)
+	shutdownHooksMu.Unlock()
+
+	for _, hook := range hooks {
+		if err := hook.fn(ctx); err != nil {
+			slog.Error("Shutdown hook failed", "hook", hook.name, "err", err)
+		}
+	}
+}
+
+// HTTP Handlers
+
+func handleHealthz(w http.ResponseWriter, r *http.Request) {
+	writeJSON(w, http.StatusOK, map[string]string{"status": "ok"})
+}
+
+func handleReadyz(w http.ResponseWriter, r *http.Request) {
+	if isShuttingDown() {
+		writeJSON(w, http.StatusServiceUnavailable, map[string]string{"status": "shutting down"})
+		return
+	}
+	writeJSON(w, http.StatusOK, map[string]string{"status": "ready"})
+}
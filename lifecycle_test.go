@@ -0,0 +1,124 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"net"
+	"net/http"
+	"reflect"
+	"sync"
+	"testing"
+	"time"
+)
+
+// resetShutdown puts the shutdown state back once the test is over, as
+// shutdown is otherwise one-way
+func resetShutdown(t *testing.T) {
+	t.Cleanup(func() {
+		shuttingDown.Store(false)
+		shutdownCh = make(chan struct{})
+		shutdownOnce = sync.Once{}
+		shutdownHooksMu.Lock()
+		shutdownHooks = nil
+		shutdownHooksMu.Unlock()
+	})
+}
+
+func TestReadyz(t *testing.T) {
+	ClearRooms()
+	resetShutdown(t)
+	mux := newRouter()
+
+	if rec := serve(mux, http.MethodGet, "/readyz", ""); rec.Code != http.StatusOK {
+		t.Fatalf("Expected 200 before shutdown, got %d", rec.Code)
+	}
+
+	beginShutdown()
+	beginShutdown() // Safe to call twice
+	if rec := serve(mux, http.MethodGet, "/readyz", ""); rec.Code != http.StatusServiceUnavailable {
+		t.Errorf("Expected 503 once shutdown began, got %d", rec.Code)
+	}
+	if rec := serve(mux, http.MethodGet, "/healthz", ""); rec.Code != http.StatusOK {
+		t.Errorf("Expected the process to stay healthy, got %d", rec.Code)
+	}
+	select {
+	case <-shutdownStarted():
+	default:
+		t.Error("Expected the shutdown channel to be closed")
+	}
+}
+
+func TestDrain(t *testing.T) {
+	ClearRooms()
+	resetShutdown(t)
+
+	var (
+		mu     sync.Mutex
+		events []string
+	)
+	event := func(e string) {
+		mu.Lock()
+		defer mu.Unlock()
+		events = append(events, e)
+	}
+
+	// A request that is still open when shutdown begins
+	entered := make(chan struct{})
+	release := make(chan struct{})
+	mux := newRouter()
+	mux.HandleFunc("GET /slow", func(w http.ResponseWriter, r *http.Request) {
+		close(entered)
+		<-release
+		event("request")
+	})
+	ln, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatal(err)
+	}
+	srv := &http.Server{Handler: mux}
+	go srv.Serve(ln)
+
+	RegisterShutdownHook("first", func(ctx context.Context) error {
+		event("first")
+		return errors.New("failed")
+	})
+	RegisterShutdownHook("second", func(ctx context.Context) error {
+		event("second")
+		return nil
+	})
+
+	requestDone := make(chan error, 1)
+	go func() {
+		resp, err := http.Get("http://" + ln.Addr().String() + "/slow")
+		if err == nil {
+			resp.Body.Close()
+		}
+		requestDone <- err
+	}()
+	<-entered
+
+	drained := make(chan struct{})
+	go func() {
+		drain(srv, 5*time.Second)
+		close(drained)
+	}()
+
+	waitFor(t, "shutdown to begin", isShuttingDown)
+	time.Sleep(20 * time.Millisecond)
+	mu.Lock()
+	early := len(events)
+	mu.Unlock()
+	if early != 0 {
+		t.Errorf("Expected hooks to wait for open requests, got %v", events)
+	}
+
+	close(release)
+	<-drained
+	if err := <-requestDone; err != nil {
+		t.Errorf("Expected the open request to finish, got %v", err)
+	}
+	// A failing hook does not stop the ones after it
+	if want := []string{"request", "first", "second"}; !reflect.DeepEqual(events, want) {
+		t.Errorf("Expected %v, got %v", want, events)
+	}
+}
@@ -0,0 +1,462 @@
+package main
+
+import (
+	"bufio"
+	"errors"
+	"fmt"
+	"log/slog"
+	"net"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/dfturn/crossclues2/internal/board"
+)
+
+// The line protocol is a plain-text alternative to the HTTP API for playing
+// over telnet or netcat and for scripting. Each command is one line. Each
+// reply is one line starting with a keyword, except boards, which start
+// with "BOARD" and end with "END". Errors are "ERR <CODE> <message>" using
+// the codes from errors.go. Once in a room the board is pushed again
+// whenever the room changes. Closing the connection leaves the room.
+
+const (
+	lineIdleTimeout  = 30 * time.Minute
+	lineWriteTimeout = 10 * time.Second
+	maxLineLength    = 1024
+)
+
+const lineHelp = `HELP commands:
+HELP   CREATE <name>                  create a room and join it
+HELP   JOIN <room> <name> [password]  join a room; the password is for private rooms
+HELP   BOARD                          show the board
+HELP   HAND                           list your cards
+HELP   OK <cell>                      mark your card at a cell, e.g. B3, as guessed
+HELP   NO <cell>                      discard your card at a cell
+HELP   START                          start or restart the game
+HELP   LEAVE                          leave the room
+HELP   QUIT                           leave the room and disconnect
+HELP board cells: # guessed, x discarded by you, B3 your card, . open`
+
+// lineMarks are the board cell symbols listed in lineHelp
+var lineMarks = board.Marks{Guessed: "#", Discarded: "x", Open: "."}
+
+// serveLineProtocol accepts line protocol connections on ln until it is
+// closed or the server begins shutting down
+func serveLineProtocol(ln net.Listener) error {
+	var (
+		mu    sync.Mutex
+		conns = make(map[net.Conn]bool)
+	)
+	go func() {
+		<-shutdownStarted()
+		ln.Close()
+		mu.Lock()
+		defer mu.Unlock()
+		for conn := range conns {
+			conn.Close()
+		}
+	}()
+
+	for {
+		conn, err := ln.Accept()
+		if err != nil {
+			if isShuttingDown() || errors.Is(err, net.ErrClosed) {
+				return nil
+			}
+			return err
+		}
+
+		mu.Lock()
+		conns[conn] = true
+		mu.Unlock()
+		go func() {
+			serveLineConn(conn)
+			mu.Lock()
+			delete(conns, conn)
+			mu.Unlock()
+		}()
+	}
+}
+
+// lineSession is one line protocol connection
+type lineSession struct {
+	conn net.Conn
+	ip   string
+
+	// mu serializes output. It is held while a command runs so that pushed
+	// boards follow the command's reply.
+	mu sync.Mutex
+	w  *bufio.Writer
+
+	room   string
+	player string
+	detach func()
+}
+
+func serveLineConn(conn net.Conn) {
+	defer conn.Close()
+
+	ip, _, err := net.SplitHostPort(conn.RemoteAddr().String())
+	if err != nil {
+		ip = conn.RemoteAddr().String()
+	}
+	s := &lineSession{conn: conn, ip: ip, w: bufio.NewWriter(conn)}
+	slog.Info("Line protocol connection opened", "remote", ip)
+
+	s.mu.Lock()
+	s.reply("HELLO crossclues; type HELP for commands")
+	s.flush()
+	s.mu.Unlock()
+
+	scanner := bufio.NewScanner(conn)
+	scanner.Buffer(make([]byte, 0, 256), maxLineLength)
+	for {
+		conn.SetReadDeadline(time.Now().Add(lineIdleTimeout))
+		if !scanner.Scan() {
+			break
+		}
+
+		s.mu.Lock()
+		quit := s.command(scanner.Text())
+		s.flush()
+		s.mu.Unlock()
+		if quit {
+			break
+		}
+	}
+	if errors.Is(scanner.Err(), bufio.ErrTooLong) {
+		s.mu.Lock()
+		s.reply("ERR " + CodeInvalidRequest + " line too long")
+		s.flush()
+		s.mu.Unlock()
+	}
+
+	s.mu.Lock()
+	s.leave()
+	s.mu.Unlock()
+	slog.Info("Line protocol connection closed", "remote", ip)
+}
+
+// command runs one line of input and reports whether the client quit. The
+// caller holds s.mu.
+func (s *lineSession) command(line string) (quit bool) {
+	verb, args, _ := strings.Cut(strings.TrimSpace(line), " ")
+	args = strings.TrimSpace(args)
+
+	switch strings.ToUpper(verb) {
+	case "":
+		// Ignore blank lines
+	case "HELP":
+		s.reply(lineHelp)
+
+	case "CREATE":
+		if s.allow("create") && s.notInRoom() {
+			if isShuttingDown() {
+				s.replyError(ErrShuttingDown)
+				break
+			}
+			room, playerName, err := CreateRoomWithOptions("", args, RoomOptions{GridSize: config.DefaultGridSize})
+			if err != nil {
+				s.replyError(err)
+				break
+			}
+			s.reply(fmt.Sprintf("CREATED %s %s", room.RoomCode, playerName))
+			s.attach(room.RoomCode, playerName)
+			s.writeBoard()
+		}
+
+	case "JOIN":
+		roomCode, rest, _ := strings.Cut(args, " ")
+		roomCode = normalizeRoomCode(roomCode)
+		if s.allow("join") && s.allowRoom("join", roomCode) && s.notInRoom() {
+			s.join(roomCode, rest)
+		}
+
+	case "BOARD":
+		if s.inRoom() {
+			s.writeBoard()
+		}
+
+	case "HAND":
+		if s.inRoom() {
+			state, err := GetGameState(s.room, s.player)
+			if err != nil {
+				s.replyError(err)
+				break
+			}
+			labels := []string{"HAND"}
+			for _, card := range state.PlayerCards {
+				labels = append(labels, board.Label(card.Row, card.Column))
+			}
+			s.reply(strings.Join(labels, " "))
+		}
+
+	case "OK", "NO":
+		if s.inRoom() && s.allow("action") && s.allowRoom("action", s.room) {
+			s.guess(args, strings.EqualFold(verb, "OK"))
+		}
+
+	case "START":
+		if s.inRoom() && s.allow("action") && s.allowRoom("action", s.room) {
+			if err := StartGame(s.room); err != nil {
+				s.replyError(err)
+				break
+			}
+			s.reply("STARTED " + s.room)
+		}
+
+	case "LEAVE":
+		if s.inRoom() {
+			room := s.room
+			s.leave()
+			s.reply("LEFT " + room)
+		}
+
+	case "QUIT", "EXIT":
+		s.leave()
+		s.reply("BYE")
+		return true
+
+	default:
+		s.reply(fmt.Sprintf("ERR %s unknown command %q; type HELP for commands", CodeInvalidRequest, verb))
+	}
+	return false
+}
+
+func (s *lineSession) guess(label string, correct bool) {
+	state, err := GetGameState(s.room, s.player)
+	if err != nil {
+		s.replyError(err)
+		return
+	}
+	row, col, ok := board.ParseLabel(label, state.GridSize)
+	if !ok {
+		s.replyError(ErrInvalidCell)
+		return
+	}
+
+	gameOver, err := SubmitGuess(s.room, s.player, row, col, correct)
+	if err != nil {
+		s.replyError(err)
+		return
+	}
+	if correct {
+		s.reply("GUESSED " + board.Label(row, col))
+	} else {
+		s.reply("DISCARDED " + board.Label(row, col))
+	}
+	if gameOver {
+		s.reply("GAMEOVER " + s.room)
+	}
+}
+
+// join joins a room and starts pushing its board. Names may contain spaces,
+// so the last word is taken as the password only when the room is private.
+func (s *lineSession) join(roomCode, args string) {
+	name, password := args, ""
+	if room, exists := getRoom(roomCode); exists && room.IsPrivate() {
+		if i := strings.LastIndex(args, " "); i >= 0 {
+			name, password = args[:i], args[i+1:]
+		}
+	}
+
+	// Wrong password attempts are limited per client and room, as over HTTP
+	attemptKey := roomCode + "|" + s.ip
+	if blocked, wait := passwordLimiter.Blocked(attemptKey, time.Now()); blocked {
+		s.replyRateLimited(wait)
+		return
+	}
+
+	name = normalizePlayerName(name)
+	cardsDealt, err := JoinRoomWithPassword(roomCode, name, password)
+	if err != nil {
+		if errors.Is(err, ErrWrongPassword) {
+			passwordLimiter.Allow(attemptKey, time.Now())
+		}
+		s.replyError(err)
+		return
+	}
+	s.reply(fmt.Sprintf("JOINED %s %s %d", roomCode, name, cardsDealt))
+	s.attach(roomCode, name)
+	s.writeBoard()
+}
+
+// attach starts pushing the room's board to the client whenever it changes
+func (s *lineSession) attach(roomCode, player string) {
+	s.room, s.player = roomCode, player
+
+	changes, stop := WatchRoom(roomCode)
+	done := make(chan struct{})
+	s.detach = func() {
+		stop()
+		close(done)
+	}
+
+	go func() {
+		for {
+			select {
+			case <-done:
+				return
+			case <-changes:
+			}
+
+			s.mu.Lock()
+			select {
+			case <-done:
+				// Left the room while waiting for the lock
+			default:
+				if _, exists := getRoom(roomCode); exists {
+					s.writeBoard()
+				} else {
+					s.replyError(ErrRoomNotFound)
+					s.room, s.player = "", ""
+					s.detach()
+					s.detach = nil
+				}
+				s.flush()
+			}
+			s.mu.Unlock()
+		}
+	}()
+}
+
+// leave stops pushing boards and removes the player from their room
+func (s *lineSession) leave() {
+	if s.room == "" {
+		return
+	}
+	s.detach()
+	s.detach = nil
+	if err := LeaveRoom(s.room, s.player); err != nil && !errors.Is(err, ErrRoomNotFound) {
+		slog.Warn("Line protocol leave failed", "room", s.room, "player", s.player, "err", err)
+	}
+	s.room, s.player = "", ""
+}
+
+func (s *lineSession) inRoom() bool {
+	if s.room == "" {
+		s.reply("ERR " + CodeInvalidRequest + " not in a room; use CREATE or JOIN first")
+		return false
+	}
+	return true
+}
+
+func (s *lineSession) notInRoom() bool {
+	if s.room != "" {
+		s.reply("ERR " + CodeInvalidRequest + " already in room " + s.room + "; LEAVE first")
+		return false
+	}
+	return true
+}
+
+// allow applies the per-client rate limit for a kind of request (see
+// rateLimited)
+func (s *lineSession) allow(kind string) bool {
+	return s.checkLimit(clientLimiter(kind), kind+"|"+s.ip)
+}
+
+// allowRoom applies the per-room rate limit for a kind of request, if the
+// room exists
+func (s *lineSession) allowRoom(kind, roomCode string) bool {
+	if _, exists := getRoom(roomCode); !exists {
+		return true
+	}
+	return s.checkLimit(roomLimiter(kind), roomCode)
+}
+
+func (s *lineSession) checkLimit(limiter *rateLimiter, key string) bool {
+	ok, wait := limiter.Allow(key, time.Now())
+	if !ok {
+		s.replyRateLimited(wait)
+	}
+	return ok
+}
+
+func (s *lineSession) replyRateLimited(wait time.Duration) {
+	seconds := max(1, int(wait.Round(time.Second).Seconds()))
+	s.reply(fmt.Sprintf("ERR %s %s; retry in %ds", CodeRateLimited, ErrRateLimited.Message, seconds))
+}
+
+// writeBoard writes the board as the player sees it
+func (s *lineSession) writeBoard() {
+	state, err := GetGameState(s.room, s.player)
+	if err != nil {
+		s.replyError(err)
+		return
+	}
+
+	status := "waiting"
+	switch {
+	case state.GameOver:
+		status = "over"
+	case state.GameStarted:
+		status = "playing"
+	}
+	inHand := make(map[Card]bool)
+	hand := make([]string, len(state.PlayerCards))
+	for i, card := range state.PlayerCards {
+		inHand[card] = true
+		hand[i] = board.Label(card.Row, card.Column)
+	}
+
+	lines := []string{fmt.Sprintf("BOARD %s %s %d/%d", state.RoomCode, status, state.CorrectGuesses, state.TotalCells)}
+	lines = append(lines, board.Lines(state.RowWords, state.ColumnWords, lineMarks, func(row, col int) board.Cell {
+		switch cell := state.Grid[row][col]; {
+		case cell.GuessedCorrectly:
+			return board.Guessed
+		case cell.DiscardedByMe:
+			return board.Discarded
+		case inHand[Card{Row: row, Column: col}]:
+			return board.Held
+		}
+		return board.Open
+	})...)
+	lines = append(lines,
+		"PLAYERS "+strings.Join(state.Players, ", "),
+		strings.TrimSpace("HAND "+strings.Join(hand, " ")),
+	)
+	if state.GameOver {
+		lines = append(lines, s.shareLines()...)
+	}
+	lines = append(lines, "END")
+	s.reply(strings.Join(lines, "\n"))
+}
+
+// shareLines returns the shareable summary of the room's last finished game,
+// one "SHARE" line per line of text. There is no request to take the
+// server's address from, so the link is left out unless a public URL is
+// configured.
+func (s *lineSession) shareLines() []string {
+	share, err := lastShare(s.room, strings.TrimRight(config.PublicURL, "/"))
+	if err != nil {
+		return nil
+	}
+	text := share.Text
+	if config.PublicURL == "" {
+		text = strings.TrimSuffix(text, "\n"+share.Link)
+	}
+	var lines []string
+	for _, line := range strings.Split(text, "\n") {
+		lines = append(lines, "SHARE "+line)
+	}
+	return lines
+}
+
+// reply queues one or more lines for the client
+func (s *lineSession) reply(text string) {
+	for _, line := range strings.Split(text, "\n") {
+		s.w.WriteString(line)
+		s.w.WriteString("\r\n")
+	}
+}
+
+func (s *lineSession) replyError(err error) {
+	e, _ := httpError(err)
+	s.reply(fmt.Sprintf("ERR %s %s", e.Code, e.Message))
+}
+
+func (s *lineSession) flush() {
+	s.conn.SetWriteDeadline(time.Now().Add(lineWriteTimeout))
+	s.w.Flush()
+}
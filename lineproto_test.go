@@ -0,0 +1,160 @@
+package main
+
+import (
+	"bufio"
+	"net"
+	"strings"
+	"testing"
+	"time"
+)
+
+type lineClient struct {
+	t    *testing.T
+	conn net.Conn
+	r    *bufio.Scanner
+}
+
+func dialLine(t *testing.T, addr string) *lineClient {
+	t.Helper()
+	conn, err := net.Dial("tcp", addr)
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { conn.Close() })
+	c := &lineClient{t: t, conn: conn, r: bufio.NewScanner(conn)}
+	c.expect("HELLO")
+	return c
+}
+
+func (c *lineClient) send(line string) {
+	c.conn.Write([]byte(line + "\r\n"))
+}
+
+// expect reads lines until one starts with prefix, and returns it
+func (c *lineClient) expect(prefix string) string {
+	c.t.Helper()
+	c.conn.SetReadDeadline(time.Now().Add(2 * time.Second))
+	for c.r.Scan() {
+		if line := c.r.Text(); strings.HasPrefix(line, prefix) {
+			return line
+		}
+	}
+	c.t.Fatalf("Expected a line starting with %q: %v", prefix, c.r.Err())
+	return ""
+}
+
+func TestLineProtocol(t *testing.T) {
+	ClearRooms()
+	ln, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer ln.Close()
+	go serveLineProtocol(ln)
+
+	alice := dialLine(t, ln.Addr().String())
+	alice.send("BOARD")
+	alice.expect("ERR INVALID_REQUEST")
+
+	alice.send("create Alice")
+	fields := strings.Fields(alice.expect("CREATED"))
+	roomCode := fields[1]
+	alice.expect("END")
+
+	bob := dialLine(t, ln.Addr().String())
+	bob.send("JOIN " + strings.ToLower(roomCode) + " Bob")
+	if got := bob.expect("JOINED"); got != "JOINED "+roomCode+" Bob 2" {
+		t.Errorf("Expected JOINED %s Bob 2, got %q", roomCode, got)
+	}
+	bob.expect("END")
+
+	// Alice is pushed a new board when Bob joins
+	if got := alice.expect("PLAYERS"); got != "PLAYERS Alice, Bob" {
+		t.Errorf("Expected both players, got %q", got)
+	}
+
+	bob.send("START")
+	bob.expect("STARTED")
+	if got := alice.expect("BOARD"); !strings.Contains(got, "playing") {
+		t.Errorf("Expected a playing board, got %q", got)
+	}
+
+	bob.send("HAND")
+	hand := strings.Fields(bob.expect("HAND"))
+	if len(hand) < 2 {
+		t.Fatalf("Expected Bob to hold cards, got %v", hand)
+	}
+	bob.send("OK " + strings.ToLower(hand[1]))
+	if got := bob.expect("GUESSED"); got != "GUESSED "+hand[1] {
+		t.Errorf("Expected GUESSED %s, got %q", hand[1], got)
+	}
+	if got := alice.expect("BOARD"); !strings.HasSuffix(got, " 1/25") {
+		t.Errorf("Expected one correct guess, got %q", got)
+	}
+	alice.expect("END")
+
+	bob.send("NO Z9")
+	bob.expect("ERR INVALID_CELL")
+	bob.send("DANCE")
+	bob.expect("ERR INVALID_REQUEST")
+
+	bob.send("QUIT")
+	bob.expect("BYE")
+	if got := alice.expect("PLAYERS"); got != "PLAYERS Alice" {
+		t.Errorf("Expected Bob to have left, got %q", got)
+	}
+}
+
+func TestLineProtocolPrivateRoom(t *testing.T) {
+	ClearRooms()
+	CreateRoomWithOptions("HUSH", "Alice", RoomOptions{GridSize: 3, Password: "sesame"})
+	ln, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer ln.Close()
+	go serveLineProtocol(ln)
+
+	bob := dialLine(t, ln.Addr().String())
+	bob.send("JOIN HUSH Bob Smith")
+	bob.expect("ERR WRONG_PASSWORD")
+	bob.send("JOIN hush Bob Smith sesame")
+	if got := bob.expect("JOINED"); got != "JOINED HUSH Bob Smith 2" {
+		t.Errorf("Expected Bob Smith to join with the password, got %q", got)
+	}
+
+	// In a public room the whole rest of the line is the name
+	CreateRoom("OPEN", 3, "Alice")
+	carol := dialLine(t, ln.Addr().String())
+	carol.send("JOIN OPEN Carol Ann")
+	if got := carol.expect("JOINED"); got != "JOINED OPEN Carol Ann 2" {
+		t.Errorf("Expected Carol Ann to join, got %q", got)
+	}
+}
+
+func TestLineProtocolShare(t *testing.T) {
+	ClearRooms()
+	defer func(url string) { config.PublicURL = url }(config.PublicURL)
+	config.PublicURL = "https://crossclues.example/"
+	CreateRoom("LINESHARE", 3, "Alice")
+	ln, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer ln.Close()
+	go serveLineProtocol(ln)
+
+	bob := dialLine(t, ln.Addr().String())
+	bob.send("JOIN LINESHARE Bob")
+	bob.expect("END")
+	StartGame("LINESHARE")
+	finishGame(t, "LINESHARE")
+
+	// The final board carries the summary, ending with the link
+	bob.expect("BOARD LINESHARE over")
+	if got := bob.expect("SHARE"); !strings.HasPrefix(got, "SHARE CrossClues ") {
+		t.Errorf("Expected the summary's header, got %q", got)
+	}
+	bob.expect("SHARE https://crossclues.example/?room=LINESHARE")
+	bob.expect("END")
+}
@@ -0,0 +1,144 @@
+package main
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+	"sort"
+	"strings"
+	"time"
+)
+
+// ListLobbyRooms returns the public rooms whose game hasn't started, the
+// fullest (then oldest) first
+func ListLobbyRooms() []LobbyRoom {
+	roomsMu.RLock()
+	all := make([]*Room, 0, len(rooms))
+	for _, room := range rooms {
+		all = append(all, room)
+	}
+	roomsMu.RUnlock()
+
+	type entry struct {
+		room      LobbyRoom
+		createdAt time.Time
+	}
+	now := time.Now()
+	var entries []entry
+	for _, room := range all {
+		room.mu.RLock()
+		if room.Public && !room.GameStarted {
+			entries = append(entries, entry{
+				room: LobbyRoom{
+					RoomCode:    room.RoomCode,
+					PlayerCount: len(room.Players),
+					MaxPlayers:  room.MaxPlayers,
+					GridSize:    room.GridSize,
+					WordPack:    room.WordPack,
+					Private:     room.IsPrivate(),
+					AgeSeconds:  int64(now.Sub(room.CreatedAt).Seconds()),
+				},
+				createdAt: room.CreatedAt,
+			})
+		}
+		room.mu.RUnlock()
+	}
+
+	sort.Slice(entries, func(i, j int) bool {
+		a, b := entries[i], entries[j]
+		if a.room.PlayerCount != b.room.PlayerCount {
+			return a.room.PlayerCount > b.room.PlayerCount
+		}
+		return a.createdAt.Before(b.createdAt)
+	})
+
+	lobby := make([]LobbyRoom, len(entries))
+	for i, e := range entries {
+		lobby[i] = e.room
+	}
+	return lobby
+}
+
+// QuickJoin puts a player into the best-fitting open public room, or creates
+// a new public room if none fits. A room fits if it has no password, has
+// space, and matches the grid size and word pack when those are given.
+// Fuller rooms are preferred so games start sooner.
+func QuickJoin(playerName string, gridSize int, wordPack string) (roomCode string, cardsDealt int, created bool, err error) {
+	for _, candidate := range ListLobbyRooms() {
+		if candidate.Private ||
+			(candidate.MaxPlayers > 0 && candidate.PlayerCount >= candidate.MaxPlayers) ||
+			(gridSize != 0 && candidate.GridSize != gridSize) ||
+			(wordPack != "" && candidate.WordPack != wordPack) {
+			continue
+		}
+
+		cardsDealt, err := JoinRoom(candidate.RoomCode, playerName)
+		switch {
+		case err == nil:
+			return candidate.RoomCode, cardsDealt, false, nil
+		case errors.Is(err, ErrRoomNotFound), errors.Is(err, ErrRoomFull), errors.Is(err, ErrPlayerExists):
+			// Lost a race or the name is taken there; try the next room
+			continue
+		default:
+			return "", 0, false, err
+		}
+	}
+
+	room, creator, err := CreateRoomWithOptions("", playerName, RoomOptions{
+		GridSize: gridSize,
+		WordPack: wordPack,
+		Public:   true,
+	})
+	if err != nil {
+		return "", 0, false, err
+	}
+
+	room.mu.RLock()
+	cardsDealt = len(room.PlayerHands[creator])
+	room.mu.RUnlock()
+	return room.RoomCode, cardsDealt, true, nil
+}
+
+// HTTP Handlers
+
+func handleGetLobby(w http.ResponseWriter, r *http.Request) {
+	writeJSON(w, http.StatusOK, LobbyResponse{Rooms: ListLobbyRooms()})
+}
+
+func handleQuickJoin(w http.ResponseWriter, r *http.Request) {
+	if isShuttingDown() {
+		writeError(w, ErrShuttingDown)
+		return
+	}
+
+	var req QuickJoinRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		writeError(w, ErrInvalidBody)
+		return
+	}
+
+	logPlayer(r, req.PlayerName)
+	if strings.TrimSpace(req.PlayerName) == "" {
+		writeError(w, ErrPlayerNameRequired)
+		return
+	}
+
+	roomCode, cardsDealt, created, err := QuickJoin(req.PlayerName, req.GridSize, req.WordPack)
+	if err != nil {
+		writeError(w, err)
+		return
+	}
+	logRoom(r, roomCode)
+
+	message := "Joined room successfully"
+	if created {
+		message = "Created a new public room"
+	}
+	writeJSON(w, http.StatusOK, QuickJoinResponse{
+		RoomCode:   roomCode,
+		PlayerName: normalizePlayerName(req.PlayerName),
+		CardsDealt: cardsDealt,
+		Created:    created,
+		Message:    message,
+	})
+}
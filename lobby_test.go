@@ -0,0 +1,67 @@
+package main
+
+import "testing"
+
+func TestListLobbyRooms(t *testing.T) {
+	ClearRooms()
+
+	CreateRoomWithOptions("PUBLIC1", "Alice", RoomOptions{GridSize: 5, Public: true})
+	CreateRoomWithOptions("PUBLIC2", "Bob", RoomOptions{GridSize: 4, Public: true})
+	JoinRoom("PUBLIC2", "Charlie")
+	CreateRoom("UNLISTED", 5, "Dave")
+	CreateRoomWithOptions("STARTED", "Erin", RoomOptions{GridSize: 5, Public: true})
+	JoinRoom("STARTED", "Frank")
+	StartGame("STARTED")
+
+	lobby := ListLobbyRooms()
+	if len(lobby) != 2 {
+		t.Fatalf("Expected 2 lobby rooms, got %d", len(lobby))
+	}
+	// Fuller rooms come first
+	if lobby[0].RoomCode != "PUBLIC2" || lobby[0].PlayerCount != 2 || lobby[0].GridSize != 4 {
+		t.Errorf("Expected PUBLIC2 with 2 players first, got %+v", lobby[0])
+	}
+	if lobby[1].RoomCode != "PUBLIC1" {
+		t.Errorf("Expected PUBLIC1 second, got %+v", lobby[1])
+	}
+}
+
+func TestQuickJoin(t *testing.T) {
+	ClearRooms()
+	defer func(max int) { config.MaxPlayersPerRoom = max }(config.MaxPlayersPerRoom)
+	config.MaxPlayersPerRoom = 2
+
+	CreateRoomWithOptions("SMALL", "Alice", RoomOptions{GridSize: 3, Public: true})
+	CreateRoomWithOptions("LOCKED", "Bob", RoomOptions{GridSize: 5, Public: true, Password: "secret"})
+
+	// Grid size filter skips SMALL, password skips LOCKED, so a room is created
+	// The name is normalized before it is seated, and the cards dealt to
+	// the normalized name are counted
+	code, cardsDealt, created, err := QuickJoin("  Charlie ", 5, "")
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	if !created || code == "SMALL" || code == "LOCKED" {
+		t.Errorf("Expected a new room to be created, got %s (created=%v)", code, created)
+	}
+	if cardsDealt != 2 {
+		t.Errorf("Expected 2 cards dealt, got %d", cardsDealt)
+	}
+
+	// Without filters the fullest open room wins; SMALL and the new room tie
+	// on players so the older SMALL is chosen
+	code, _, created, err = QuickJoin("Dave", 0, "")
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	if created || code != "SMALL" {
+		t.Errorf("Expected to join SMALL, got %s (created=%v)", code, created)
+	}
+
+	// SMALL is now full, so the next player goes to Charlie's room
+	room, _ := getRoom("SMALL")
+	code, _, _, _ = QuickJoin("Erin", 0, "")
+	if code == "SMALL" || len(room.Players) != 2 {
+		t.Errorf("Expected full room SMALL to be skipped, got %s", code)
+	}
+}
@@ -0,0 +1,101 @@
+package main
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"fmt"
+	"io"
+	"log/slog"
+	"net/http"
+	"regexp"
+	"time"
+)
+
+// newLogger returns a slog.Logger writing JSON (for production) or text
+// (for local development) to w
+func newLogger(w io.Writer, format string) (*slog.Logger, error) {
+	switch format {
+	case "json":
+		return slog.New(slog.NewJSONHandler(w, nil)), nil
+	case "text":
+		return slog.New(slog.NewTextHandler(w, nil)), nil
+	default:
+		return nil, fmt.Errorf("unknown log format %q (want json or text)", format)
+	}
+}
+
+// Request IDs
+
+const requestIDHeader = "X-Request-ID"
+
+// validRequestID matches client-supplied request IDs we are willing to echo
+var validRequestID = regexp.MustCompile(`^[A-Za-z0-9._-]{1,64}$`)
+
+func newRequestID() string {
+	b := make([]byte, 8)
+	rand.Read(b)
+	return hex.EncodeToString(b)
+}
+
+// requestLogInfo collects per-request fields for the access log. Handlers
+// fill in the player (and room, when it isn't in the path) once they've
+// decoded the request.
+type requestLogInfo struct {
+	id     string
+	route  string
+	room   string
+	player string
+}
+
+type requestLogKey struct{}
+
+// logPlayer records the player a request acts on for the access log
+func logPlayer(r *http.Request, playerName string) {
+	if info, ok := r.Context().Value(requestLogKey{}).(*requestLogInfo); ok {
+		info.player = playerName
+	}
+}
+
+// logRoom records the room a request acts on for the access log
+func logRoom(r *http.Request, roomCode string) {
+	if info, ok := r.Context().Value(requestLogKey{}).(*requestLogInfo); ok {
+		info.room = roomCode
+	}
+}
+
+// accessLog assigns each request an ID, echoes it in the X-Request-ID header
+// and logs the request once it completes. route is the matched pattern,
+// which keeps the logged route low-cardinality.
+func accessLog(route string, next http.HandlerFunc) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		start := time.Now()
+
+		id := r.Header.Get(requestIDHeader)
+		if !validRequestID.MatchString(id) {
+			id = newRequestID()
+		}
+		w.Header().Set(requestIDHeader, id)
+
+		info := &requestLogInfo{id: id, route: route, room: normalizeRoomCode(r.PathValue("code")), player: r.URL.Query().Get("playerName")}
+		r = r.WithContext(context.WithValue(r.Context(), requestLogKey{}, info))
+		rec := &statusRecorder{ResponseWriter: w, status: http.StatusOK}
+
+		next(rec, r)
+
+		level := slog.LevelInfo
+		if rec.status >= 500 {
+			level = slog.LevelError
+		}
+		slog.LogAttrs(r.Context(), level, "request",
+			slog.String("request_id", id),
+			slog.String("method", r.Method),
+			slog.String("route", info.route),
+			slog.String("room", info.room),
+			slog.String("player", info.player),
+			slog.Int("status", rec.status),
+			slog.Int("bytes", rec.bytes),
+			slog.Duration("latency", time.Since(start)),
+		)
+	}
+}
@@ -0,0 +1,96 @@
+package main
+
+import (
+	"bytes"
+	"encoding/json"
+	"log/slog"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+)
+
+// captureLogs sends the default logger's output to a buffer for the rest of
+// the test
+func captureLogs(t *testing.T) *bytes.Buffer {
+	var buf bytes.Buffer
+	prev := slog.Default()
+	slog.SetDefault(slog.New(slog.NewJSONHandler(&buf, nil)))
+	t.Cleanup(func() { slog.SetDefault(prev) })
+	return &buf
+}
+
+// accessLines returns the access-log entries written so far
+func accessLines(t *testing.T, buf *bytes.Buffer) []map[string]any {
+	t.Helper()
+	var lines []map[string]any
+	for _, line := range strings.Split(strings.TrimSpace(buf.String()), "\n") {
+		var entry map[string]any
+		if err := json.Unmarshal([]byte(line), &entry); err != nil {
+			t.Fatalf("Invalid log line %q: %v", line, err)
+		}
+		if entry["msg"] == "request" {
+			lines = append(lines, entry)
+		}
+	}
+	buf.Reset()
+	return lines
+}
+
+func TestAccessLog(t *testing.T) {
+	ClearRooms()
+	logs := captureLogs(t)
+	mux := newRouter()
+
+	// A valid incoming request ID is reused; the handler names the player
+	// and the room it created
+	req := httptest.NewRequest(http.MethodPost, "/api/v1/rooms", strings.NewReader(`{"playerName": "Alice", "roomCode": "LOGS"}`))
+	req.Header.Set(requestIDHeader, "trace-42")
+	rec := httptest.NewRecorder()
+	mux.ServeHTTP(rec, req)
+	if got := rec.Header().Get(requestIDHeader); got != "trace-42" {
+		t.Errorf("Expected the request ID to be echoed, got %q", got)
+	}
+	lines := accessLines(t, logs)
+	if len(lines) != 1 {
+		t.Fatalf("Expected 1 access-log line, got %d", len(lines))
+	}
+	want := map[string]any{
+		"level":      "INFO",
+		"request_id": "trace-42",
+		"method":     "POST",
+		"route":      "/api/v1/rooms",
+		"room":       "LOGS",
+		"player":     "Alice",
+		"status":     float64(http.StatusCreated),
+		"bytes":      float64(rec.Body.Len()),
+	}
+	for key, value := range want {
+		if lines[0][key] != value {
+			t.Errorf("Expected %s=%v, got %v", key, value, lines[0][key])
+		}
+	}
+
+	// An invalid one is replaced, and errors carry the ID in their body
+	req = httptest.NewRequest(http.MethodGet, "/api/v1/rooms/NOPE/state?playerName=Bob", nil)
+	req.Header.Set(requestIDHeader, "not a valid id!")
+	rec = httptest.NewRecorder()
+	mux.ServeHTTP(rec, req)
+	id := rec.Header().Get(requestIDHeader)
+	if !validRequestID.MatchString(id) || id == "not a valid id!" {
+		t.Errorf("Expected a generated request ID, got %q", id)
+	}
+	var errResp ErrorResponse
+	json.NewDecoder(bytes.NewReader(rec.Body.Bytes())).Decode(&errResp)
+	if errResp.RequestID != id {
+		t.Errorf("Expected requestId %q in the error body, got %q", id, errResp.RequestID)
+	}
+	lines = accessLines(t, logs)
+	if len(lines) != 1 {
+		t.Fatalf("Expected 1 access-log line, got %d", len(lines))
+	}
+	if line := lines[0]; line["request_id"] != id || line["status"] != float64(http.StatusNotFound) ||
+		line["room"] != "NOPE" || line["player"] != "Bob" || line["bytes"] != float64(rec.Body.Len()) {
+		t.Errorf("Unexpected access-log line %v", line)
+	}
+}
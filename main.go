@@ -1,39 +1,150 @@
 package main
 
 import (
-	"log"
+	"context"
+	"errors"
+	"log/slog"
 	"mime"
+	"net"
 	"net/http"
 	"os"
+	"os/signal"
+	"path"
+	"path/filepath"
+	"syscall"
+	"time"
+)
+
+// Server timeouts
+const (
+	readTimeout     = 15 * time.Second
+	writeTimeout    = 30 * time.Second
+	idleTimeout     = 120 * time.Second
+	shutdownTimeout = 20 * time.Second
 )
 
 func main() {
-	// API routes
-	http.HandleFunc("/api/rooms", enableCORS(handleRooms))
-	http.HandleFunc("/api/rooms/", enableCORS(handleRooms))
+	cfg, printConfig, err := loadConfig(os.Args[1:], os.Getenv)
+	if printConfig {
+		cfg.Redacted().WriteTo(os.Stdout)
+	}
+	if err != nil {
+		fatal("Invalid configuration", err)
+	}
+	if printConfig {
+		return
+	}
+	config = cfg
+
+	logger, err := newLogger(os.Stderr, config.LogFormat)
+	if err != nil {
+		fatal("Invalid configuration", err)
+	}
+	slog.SetDefault(logger)
+	for _, warning := range config.warnings {
+		slog.Warn(warning)
+	}
+	if config.PublicURL == "" {
+		slog.Warn("No public URL is set, so links in shared results are built from the Host header clients send; set -public-url")
+	}
+
+	if config.WordPackDir != "" {
+		if err := loadWordPacks(config.WordPackDir, 2*config.MaxGridSize); err != nil {
+			fatal("Failed to load word packs", err)
+		}
+	}
+	if config.AssociationsFile != "" {
+		if err := loadAssociations(config.AssociationsFile); err != nil {
+			fatal("Failed to load word associations", err)
+		}
+	}
+	for _, name := range WordPackNames() {
+		if missing := associations.unassociated(wordPacks[name]); len(missing) > 0 {
+			slog.Warn("Word pack has words without associations; list them in -associations-file for clue suggestions",
+				"pack", name, "words", missing)
+		}
+	}
+
+	configureRateLimits(config.RateLimits)
+
+	slog.Info("Configuration loaded", "config", config.Redacted(), "wordPacks", WordPackNames())
+
+	// Admin routes are disabled unless an admin secret is configured
+	adminSecret = config.AdminSecret
+	if adminSecret != "" {
+		slog.Info("Admin API enabled", "path", "/api/v1/admin/")
+	}
+
+	mux := newRouter()
 
 	// Ensure .webp files are served with the correct MIME type
 	// Some Go stdlib versions don't register .webp by default.
 	_ = mime.AddExtensionType(".webp", "image/webp")
 
 	// Serve static frontend files if the static directory exists
-	if _, err := os.Stat("static"); err == nil {
-		fs := http.FileServer(http.Dir("static"))
-		http.HandleFunc("/", func(w http.ResponseWriter, r *http.Request) {
+	staticDir := config.StaticDir
+	if _, err := os.Stat(staticDir); err == nil {
+		fs := http.FileServer(http.Dir(staticDir))
+		mux.HandleFunc("/", func(w http.ResponseWriter, r *http.Request) {
 			// Serve index.html for all non-API routes (SPA support)
-			if r.URL.Path != "/" && !fileExists("static"+r.URL.Path) {
-				http.ServeFile(w, r, "static/index.html")
+			if r.URL.Path != "/" && !fileExists(filepath.Join(staticDir, filepath.FromSlash(path.Clean(r.URL.Path)))) {
+				http.ServeFile(w, r, filepath.Join(staticDir, "index.html"))
 				return
 			}
 			fs.ServeHTTP(w, r)
 		})
-		log.Println("Serving static files from ./static")
+		slog.Info("Serving static files", "dir", staticDir)
+	}
+
+	srv := &http.Server{
+		Addr:         config.ListenAddr,
+		Handler:      mux,
+		ReadTimeout:  readTimeout,
+		WriteTimeout: writeTimeout,
+		IdleTimeout:  idleTimeout,
+	}
+
+	if config.LineAddr != "" {
+		ln, err := net.Listen("tcp", config.LineAddr)
+		if err != nil {
+			fatal("Line protocol listener failed to start", err)
+		}
+		slog.Info("Line protocol listening", "addr", config.LineAddr)
+		go func() {
+			if err := serveLineProtocol(ln); err != nil {
+				slog.Error("Line protocol listener stopped", "err", err)
+			}
+		}()
 	}
 
-	log.Println("CrossClues server starting on :8080...")
-	if err := http.ListenAndServe(":8080", nil); err != nil {
-		log.Fatalf("Server failed to start: %v", err)
+	ctx, stop := signal.NotifyContext(context.Background(), syscall.SIGTERM, syscall.SIGINT)
+	defer stop()
+
+	serveErr := make(chan error, 1)
+	go func() {
+		slog.Info("CrossClues server starting", "addr", config.ListenAddr)
+		serveErr <- srv.ListenAndServe()
+	}()
+
+	select {
+	case err := <-serveErr:
+		if !errors.Is(err, http.ErrServerClosed) {
+			fatal("Server failed to start", err)
+		}
+		return
+	case <-ctx.Done():
 	}
+	stop()
+
+	slog.Info("Shutting down, draining open requests")
+	drain(srv, shutdownTimeout)
+	slog.Info("Server stopped")
+}
+
+// fatal logs an error and exits
+func fatal(msg string, err error) {
+	slog.Error(msg, "err", err)
+	os.Exit(1)
 }
 
 func fileExists(path string) bool {
@@ -0,0 +1,270 @@
+package main
+
+import (
+	"fmt"
+	"io"
+	"net/http"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+)
+
+// Minimal Prometheus text-format metrics. The server only needs a handful of
+// counters, gauges and histograms, so this avoids pulling in the client library.
+
+type counterVec struct {
+	name       string
+	help       string
+	labelNames []string
+	mu         sync.Mutex
+	values     map[string]float64
+}
+
+func newCounterVec(name, help string, labelNames ...string) *counterVec {
+	return &counterVec{
+		name:       name,
+		help:       help,
+		labelNames: labelNames,
+		values:     make(map[string]float64),
+	}
+}
+
+// Inc increments the counter for the given label values
+func (c *counterVec) Inc(labelValues ...string) {
+	c.Add(1, labelValues...)
+}
+
+// Add adds v to the counter for the given label values
+func (c *counterVec) Add(v float64, labelValues ...string) {
+	key := strings.Join(labelValues, "\xff")
+	c.mu.Lock()
+	c.values[key] += v
+	c.mu.Unlock()
+}
+
+func (c *counterVec) write(w io.Writer) {
+	fmt.Fprintf(w, "# HELP %s %s\n# TYPE %s counter\n", c.name, c.help, c.name)
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if len(c.labelNames) == 0 {
+		fmt.Fprintf(w, "%s %s\n", c.name, formatFloat(c.values[""]))
+		return
+	}
+	for _, key := range sortedKeys(c.values) {
+		fmt.Fprintf(w, "%s%s %s\n", c.name, formatLabels(c.labelNames, key, "", ""), formatFloat(c.values[key]))
+	}
+}
+
+func writeGauge(w io.Writer, name, help string, value float64) {
+	fmt.Fprintf(w, "# HELP %s %s\n# TYPE %s gauge\n", name, help, name)
+	fmt.Fprintf(w, "%s %s\n", name, formatFloat(value))
+}
+
+// roomGauges writes the room, player and game gauges from one snapshot so
+// they agree with each other and the rooms are only walked once per scrape
+type roomGauges struct{}
+
+func (roomGauges) write(w io.Writer) {
+	rooms, players, inProgress := roomGaugeValues()
+	writeGauge(w, "crossclues_active_rooms", "Number of rooms currently held in memory.", float64(rooms))
+	writeGauge(w, "crossclues_active_players", "Number of players across all rooms.", float64(players))
+	writeGauge(w, "crossclues_games_in_progress", "Number of games started and not yet over.", float64(inProgress))
+}
+
+type histogramSeries struct {
+	counts []uint64
+	count  uint64
+	sum    float64
+}
+
+type histogramVec struct {
+	name       string
+	help       string
+	labelNames []string
+	buckets    []float64
+	mu         sync.Mutex
+	series     map[string]*histogramSeries
+}
+
+func newHistogramVec(name, help string, buckets []float64, labelNames ...string) *histogramVec {
+	return &histogramVec{
+		name:       name,
+		help:       help,
+		labelNames: labelNames,
+		buckets:    buckets,
+		series:     make(map[string]*histogramSeries),
+	}
+}
+
+// Observe records a single observation for the given label values
+func (h *histogramVec) Observe(v float64, labelValues ...string) {
+	key := strings.Join(labelValues, "\xff")
+
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	s, ok := h.series[key]
+	if !ok {
+		s = &histogramSeries{counts: make([]uint64, len(h.buckets))}
+		h.series[key] = s
+	}
+	for i, upper := range h.buckets {
+		if v <= upper {
+			s.counts[i]++
+		}
+	}
+	s.count++
+	s.sum += v
+}
+
+func (h *histogramVec) write(w io.Writer) {
+	fmt.Fprintf(w, "# HELP %s %s\n# TYPE %s histogram\n", h.name, h.help, h.name)
+
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	for _, key := range sortedKeys(h.series) {
+		s := h.series[key]
+		for i, upper := range h.buckets {
+			fmt.Fprintf(w, "%s_bucket%s %d\n", h.name, formatLabels(h.labelNames, key, "le", formatFloat(upper)), s.counts[i])
+		}
+		fmt.Fprintf(w, "%s_bucket%s %d\n", h.name, formatLabels(h.labelNames, key, "le", "+Inf"), s.count)
+		fmt.Fprintf(w, "%s_sum%s %s\n", h.name, formatLabels(h.labelNames, key, "", ""), formatFloat(s.sum))
+		fmt.Fprintf(w, "%s_count%s %d\n", h.name, formatLabels(h.labelNames, key, "", ""), s.count)
+	}
+}
+
+func sortedKeys[V any](m map[string]V) []string {
+	keys := make([]string, 0, len(m))
+	for k := range m {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	return keys
+}
+
+func formatFloat(v float64) string {
+	return strconv.FormatFloat(v, 'g', -1, 64)
+}
+
+var labelEscaper = strings.NewReplacer(`\`, `\\`, `"`, `\"`, "\n", `\n`)
+
+// formatLabels renders {name="value",...} for a joined label key, optionally
+// appending one extra label (used for histogram "le")
+func formatLabels(names []string, key, extraName, extraValue string) string {
+	var pairs []string
+	if len(names) > 0 {
+		values := strings.Split(key, "\xff")
+		for i, name := range names {
+			pairs = append(pairs, fmt.Sprintf(`%s="%s"`, name, labelEscaper.Replace(values[i])))
+		}
+	}
+	if extraName != "" {
+		pairs = append(pairs, fmt.Sprintf(`%s="%s"`, extraName, labelEscaper.Replace(extraValue)))
+	}
+	if len(pairs) == 0 {
+		return ""
+	}
+	return "{" + strings.Join(pairs, ",") + "}"
+}
+
+// Registered metrics
+
+var (
+	roomsCreatedTotal = newCounterVec("crossclues_rooms_created_total",
+		"Total number of rooms created.")
+	gamesStartedTotal = newCounterVec("crossclues_games_started_total",
+		"Total number of games started or restarted.")
+	gamesFinishedTotal = newCounterVec("crossclues_games_finished_total",
+		"Total number of games played through to the end.")
+	guessesTotal = newCounterVec("crossclues_guesses_total",
+		"Total number of guesses by result.", "result")
+	apiErrorsTotal = newCounterVec("crossclues_api_errors_total",
+		"Total number of API error responses by endpoint and status.", "endpoint", "status")
+	requestDuration = newHistogramVec("crossclues_http_request_duration_seconds",
+		"API request latency by handler.",
+		[]float64{.005, .01, .025, .05, .1, .25, .5, 1, 2.5, 5, 10}, "handler")
+)
+
+type metricWriter interface {
+	write(w io.Writer)
+}
+
+var registeredMetrics = []metricWriter{
+	roomGauges{},
+	roomsCreatedTotal,
+	gamesStartedTotal,
+	gamesFinishedTotal,
+	guessesTotal,
+	apiErrorsTotal,
+	requestDuration,
+}
+
+// roomGaugeValues counts rooms, players and in-progress games
+func roomGaugeValues() (roomCount, playerCount, inProgress int) {
+	roomsMu.RLock()
+	all := make([]*Room, 0, len(rooms))
+	for _, room := range rooms {
+		all = append(all, room)
+	}
+	roomsMu.RUnlock()
+
+	for _, room := range all {
+		room.mu.RLock()
+		playerCount += len(room.Players)
+		if room.GameStarted && !room.GameOver {
+			inProgress++
+		}
+		room.mu.RUnlock()
+	}
+	return len(all), playerCount, inProgress
+}
+
+// HTTP instrumentation
+
+// statusRecorder captures the status code and body size written by a
+// handler
+type statusRecorder struct {
+	http.ResponseWriter
+	status int
+	bytes  int
+}
+
+func (r *statusRecorder) WriteHeader(status int) {
+	r.status = status
+	r.ResponseWriter.WriteHeader(status)
+}
+
+func (r *statusRecorder) Write(b []byte) (int, error) {
+	n, err := r.ResponseWriter.Write(b)
+	r.bytes += n
+	return n, err
+}
+
+func (r *statusRecorder) Unwrap() http.ResponseWriter {
+	return r.ResponseWriter
+}
+
+// observe runs a handler, recording its latency and any error status
+func observe(w http.ResponseWriter, r *http.Request, handler string, next http.HandlerFunc) {
+	start := time.Now()
+	rec := &statusRecorder{ResponseWriter: w, status: http.StatusOK}
+
+	next(rec, r)
+
+	requestDuration.Observe(time.Since(start).Seconds(), handler)
+	if rec.status >= 400 {
+		apiErrorsTotal.Inc(handler, strconv.Itoa(rec.status))
+	}
+}
+
+func handleMetrics(w http.ResponseWriter, r *http.Request) {
+	w.Header().Set("Content-Type", "text/plain; version=0.0.4; charset=utf-8")
+	for _, m := range registeredMetrics {
+		m.write(w)
+	}
+}
@@ -0,0 +1,51 @@
+package main
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+)
+
+func TestCounterVecWrite(t *testing.T) {
+	c := newCounterVec("test_total", "A test counter.", "endpoint", "status")
+	c.Inc("join", "404")
+	c.Inc("join", "404")
+	c.Inc("guess", "400")
+
+	var buf bytes.Buffer
+	c.write(&buf)
+	out := buf.String()
+
+	for _, want := range []string{
+		"# TYPE test_total counter\n",
+		`test_total{endpoint="guess",status="400"} 1` + "\n",
+		`test_total{endpoint="join",status="404"} 2` + "\n",
+	} {
+		if !strings.Contains(out, want) {
+			t.Errorf("Expected output to contain %q, got:\n%s", want, out)
+		}
+	}
+}
+
+func TestHistogramVecWrite(t *testing.T) {
+	h := newHistogramVec("test_seconds", "A test histogram.", []float64{0.1, 1}, "handler")
+	h.Observe(0.05, "state")
+	h.Observe(0.5, "state")
+	h.Observe(5, "state")
+
+	var buf bytes.Buffer
+	h.write(&buf)
+	out := buf.String()
+
+	for _, want := range []string{
+		`test_seconds_bucket{handler="state",le="0.1"} 1` + "\n",
+		`test_seconds_bucket{handler="state",le="1"} 2` + "\n",
+		`test_seconds_bucket{handler="state",le="+Inf"} 3` + "\n",
+		`test_seconds_sum{handler="state"} 5.55` + "\n",
+		`test_seconds_count{handler="state"} 3` + "\n",
+	} {
+		if !strings.Contains(out, want) {
+			t.Errorf("Expected output to contain %q, got:\n%s", want, out)
+		}
+	}
+}
@@ -0,0 +1,65 @@
+package main
+
+import (
+	"strings"
+	"unicode"
+	"unicode/utf8"
+
+	"golang.org/x/text/cases"
+	"golang.org/x/text/unicode/norm"
+)
+
+// Player names are stored NFC-normalized with surrounding whitespace trimmed
+// and inner runs of whitespace collapsed. Two names are the same player if
+// their NFKC case-folded forms match, so "Alice", "alice " and "ＡＬＩＣＥ"
+// all refer to one player.
+
+const maxPlayerNameLength = 24
+
+// botNamePrefix starts the names of bots, which humans may not take
+const botNamePrefix = "Bot "
+
+var nameFolder = cases.Fold()
+
+// normalizePlayerName returns the canonical display form of a player name
+func normalizePlayerName(name string) string {
+	return strings.Join(strings.Fields(norm.NFC.String(name)), " ")
+}
+
+// playerNameKey returns the form used to compare player names
+func playerNameKey(name string) string {
+	return nameFolder.String(norm.NFKC.String(normalizePlayerName(name)))
+}
+
+// validatePlayerName checks a normalized player name against the length and
+// character rules, and that it is not a bot's name
+func validatePlayerName(name string) error {
+	if name == "" {
+		return ErrPlayerNameRequired
+	}
+	if utf8.RuneCountInString(name) > maxPlayerNameLength {
+		return ErrPlayerNameTooLong
+	}
+	for _, r := range name {
+		// Letters, marks, numbers, punctuation, symbols and plain spaces;
+		// no control, formatting (e.g. zero-width) or private-use runes
+		if r != ' ' && (!unicode.IsGraphic(r) || unicode.IsSpace(r)) {
+			return ErrPlayerNameInvalid
+		}
+	}
+	if strings.HasPrefix(playerNameKey(name), strings.ToLower(botNamePrefix)) {
+		return ErrPlayerNameReserved
+	}
+	return nil
+}
+
+// findPlayer returns the stored name of the player matching playerName
+func (r *Room) findPlayer(playerName string) (string, bool) {
+	key := playerNameKey(playerName)
+	for _, player := range r.Players {
+		if playerNameKey(player) == key {
+			return player, true
+		}
+	}
+	return "", false
+}
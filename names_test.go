@@ -0,0 +1,100 @@
+package main
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestNormalizePlayerName(t *testing.T) {
+	tests := []struct {
+		in, want string
+	}{
+		{"Alice", "Alice"},
+		{"  Alice  ", "Alice"},
+		{"Mary \t Jane", "Mary Jane"},
+		{"Jose\u0301", "Jos\u00e9"}, // combining accent composes to NFC
+	}
+	for _, tc := range tests {
+		if got := normalizePlayerName(tc.in); got != tc.want {
+			t.Errorf("normalizePlayerName(%q) = %q, want %q", tc.in, got, tc.want)
+		}
+	}
+}
+
+func TestValidatePlayerName(t *testing.T) {
+	tests := []struct {
+		name string
+		want error
+	}{
+		{"Alice", nil},
+		{"Zoë 🦊", nil},
+		{"O'Brien-Smith", nil},
+		{"", ErrPlayerNameRequired},
+		{strings.Repeat("a", 25), ErrPlayerNameTooLong},
+		{"Bob\x07", ErrPlayerNameInvalid},
+		{"Bob\u200b", ErrPlayerNameInvalid},
+		{"Bot 1", ErrPlayerNameReserved},
+		{"BOT Alice", ErrPlayerNameReserved},
+		{"Bot", nil},
+		{"Botany", nil},
+	}
+	for _, tc := range tests {
+		if got := validatePlayerName(tc.name); got != tc.want {
+			t.Errorf("validatePlayerName(%q) = %v, want %v", tc.name, got, tc.want)
+		}
+	}
+}
+
+func TestPlayerNamesCaseInsensitive(t *testing.T) {
+	ClearRooms()
+
+	CreateRoom("NAMETEST", 5, "Alice")
+
+	for _, name := range []string{"alice", " ALICE ", "Ａｌｉｃｅ"} {
+		if _, err := JoinRoom("NAMETEST", name); err != ErrPlayerExists {
+			t.Errorf("JoinRoom(%q): expected ErrPlayerExists, got %v", name, err)
+		}
+	}
+
+	JoinRoom("NAMETEST", "  Bob  ")
+	room, _ := getRoom("NAMETEST")
+	if !room.HasPlayer("BOB") || room.Players[1] != "Bob" {
+		t.Errorf("Expected Bob to be stored normalized, got %v", room.Players)
+	}
+
+	// Other operations resolve the stored name
+	StartGame("NAMETEST")
+	state, err := GetGameState("NAMETEST", "bob")
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	card := state.PlayerCards[0]
+	if _, err := SubmitGuess("NAMETEST", "BOB", card.Row, card.Column, false); err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	if room.Grid[card.Row][card.Column].DiscardedBy != "Bob" {
+		t.Errorf("Expected discard recorded for Bob, got %q", room.Grid[card.Row][card.Column].DiscardedBy)
+	}
+	if err := LeaveRoom("NAMETEST", "bob"); err != nil {
+		t.Errorf("Expected no error, got %v", err)
+	}
+	if _, exists := room.PlayerHands["Bob"]; exists {
+		t.Error("Expected Bob's hand to be returned to the deck")
+	}
+}
+
+func TestRoomCapacity(t *testing.T) {
+	ClearRooms()
+
+	_, _, err := CreateRoomWithOptions("CAPTEST", "P1", RoomOptions{GridSize: 5, MaxPlayers: 1})
+	if err != ErrInvalidMaxPlayers {
+		t.Errorf("Expected ErrInvalidMaxPlayers, got %v", err)
+	}
+
+	CreateRoomWithOptions("CAPTEST", "P1", RoomOptions{GridSize: 5, MaxPlayers: 3})
+	JoinRoom("CAPTEST", "P2")
+	JoinRoom("CAPTEST", "P3")
+	if _, err := JoinRoom("CAPTEST", "P4"); err != ErrRoomFull {
+		t.Errorf("Expected ErrRoomFull, got %v", err)
+	}
+}
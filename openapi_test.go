@@ -0,0 +1,262 @@
+package main
+
+import (
+	"encoding/json"
+	"go/ast"
+	"go/parser"
+	"go/token"
+	"net/http"
+	"reflect"
+	"sort"
+	"strings"
+	"testing"
+	"time"
+)
+
+// specTypes are the schema.go types described in openapi.json, keyed by
+// their component schema name
+var specTypes = map[string]reflect.Type{
+	"Card":                  reflect.TypeOf(Card{}),
+	"CellResponse":          reflect.TypeOf(CellResponse{}),
+	"CreateRoomRequest":     reflect.TypeOf(CreateRoomRequest{}),
+	"CreateRoomResponse":    reflect.TypeOf(CreateRoomResponse{}),
+	"JoinRoomRequest":       reflect.TypeOf(JoinRoomRequest{}),
+	"JoinRoomResponse":      reflect.TypeOf(JoinRoomResponse{}),
+	"StartGameResponse":     reflect.TypeOf(StartGameResponse{}),
+	"RoomMessageResponse":   reflect.TypeOf(RoomMessageResponse{}),
+	"GuessRequest":          reflect.TypeOf(GuessRequest{}),
+	"GuessResponse":         reflect.TypeOf(GuessResponse{}),
+	"GameStateResponse":     reflect.TypeOf(GameStateResponse{}),
+	"ClueResponse":          reflect.TypeOf(ClueResponse{}),
+	"CellMatch":             reflect.TypeOf(CellMatch{}),
+	"ClueMatchResponse":     reflect.TypeOf(ClueMatchResponse{}),
+	"CellRecord":            reflect.TypeOf(CellRecord{}),
+	"GameRecord":            reflect.TypeOf(GameRecord{}),
+	"GameHistoryResponse":   reflect.TypeOf(GameHistoryResponse{}),
+	"ShareResponse":         reflect.TypeOf(ShareResponse{}),
+	"AddBotRequest":         reflect.TypeOf(AddBotRequest{}),
+	"AddBotResponse":        reflect.TypeOf(AddBotResponse{}),
+	"RemoveBotRequest":      reflect.TypeOf(RemoveBotRequest{}),
+	"LobbyRoom":             reflect.TypeOf(LobbyRoom{}),
+	"LobbyResponse":         reflect.TypeOf(LobbyResponse{}),
+	"QuickJoinRequest":      reflect.TypeOf(QuickJoinRequest{}),
+	"QuickJoinResponse":     reflect.TypeOf(QuickJoinResponse{}),
+	"ErrorResponse":         reflect.TypeOf(ErrorResponse{}),
+	"AdminRoomSummary":      reflect.TypeOf(AdminRoomSummary{}),
+	"AdminRoomListResponse": reflect.TypeOf(AdminRoomListResponse{}),
+	"AdminCell":             reflect.TypeOf(AdminCell{}),
+	"AdminRoomDetail":       reflect.TypeOf(AdminRoomDetail{}),
+}
+
+// internalTypes are schema.go structs that never appear on the wire
+var internalTypes = map[string]bool{"Room": true, "Cell": true}
+
+type openAPIDoc struct {
+	Paths      map[string]map[string]any `json:"paths"`
+	Components struct {
+		Schemas map[string]map[string]any `json:"schemas"`
+	} `json:"components"`
+}
+
+func loadSpec(t *testing.T) openAPIDoc {
+	t.Helper()
+	var doc openAPIDoc
+	if err := json.Unmarshal(openAPISpec, &doc); err != nil {
+		t.Fatalf("openapi.json is not valid JSON: %v", err)
+	}
+	return doc
+}
+
+func TestOpenAPIRoutes(t *testing.T) {
+	doc := loadSpec(t)
+
+	routes := make(map[string]bool)
+	for _, rt := range append(append([]route(nil), apiRoutes...), adminRoutes...) {
+		key := strings.ToLower(rt.method) + " " + rt.path
+		routes[key] = true
+		if _, ok := doc.Paths[rt.path][strings.ToLower(rt.method)]; !ok {
+			t.Errorf("Route %s %s is missing from openapi.json", rt.method, rt.path)
+		}
+	}
+	for path, ops := range doc.Paths {
+		for method := range ops {
+			if method == "parameters" {
+				continue
+			}
+			if !routes[method+" "+path] {
+				t.Errorf("openapi.json describes %s %s, which has no route", strings.ToUpper(method), path)
+			}
+		}
+	}
+}
+
+func TestOpenAPISchemas(t *testing.T) {
+	doc := loadSpec(t)
+
+	// Every wire type declared in schema.go must be described
+	fset := token.NewFileSet()
+	file, err := parser.ParseFile(fset, "schema.go", nil, 0)
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, decl := range file.Decls {
+		gen, ok := decl.(*ast.GenDecl)
+		if !ok || gen.Tok != token.TYPE {
+			continue
+		}
+		for _, spec := range gen.Specs {
+			ts := spec.(*ast.TypeSpec)
+			if _, isStruct := ts.Type.(*ast.StructType); !isStruct || !ts.Name.IsExported() || internalTypes[ts.Name.Name] {
+				continue
+			}
+			if specTypes[ts.Name.Name] == nil {
+				t.Errorf("%s is declared in schema.go but not in openapi.json (add it to both and to specTypes)", ts.Name.Name)
+			}
+		}
+	}
+
+	for name, typ := range specTypes {
+		schema, ok := doc.Components.Schemas[name]
+		if !ok {
+			t.Errorf("Schema %s is missing from openapi.json", name)
+			continue
+		}
+		checkObjectSchema(t, doc, name, typ, schema)
+	}
+	for name := range doc.Components.Schemas {
+		if specTypes[name] == nil && name != "ErrorCode" {
+			t.Errorf("openapi.json describes schema %s, which has no Go type", name)
+		}
+	}
+}
+
+func TestOpenAPIErrorCodes(t *testing.T) {
+	doc := loadSpec(t)
+
+	var spec []string
+	for _, code := range doc.Components.Schemas["ErrorCode"]["enum"].([]any) {
+		spec = append(spec, code.(string))
+	}
+	var codes []string
+	for code := range codeStatus {
+		codes = append(codes, code)
+	}
+	sort.Strings(spec)
+	sort.Strings(codes)
+	if !reflect.DeepEqual(spec, codes) {
+		t.Errorf("ErrorCode enum in openapi.json is out of date:\nspec: %v\ncode: %v", spec, codes)
+	}
+}
+
+func TestServeOpenAPI(t *testing.T) {
+	rec := serve(newRouter(), http.MethodGet, "/api/openapi.json", "")
+	if rec.Code != http.StatusOK {
+		t.Fatalf("Expected 200, got %d", rec.Code)
+	}
+	if ct := rec.Header().Get("Content-Type"); ct != "application/json" {
+		t.Errorf("Expected application/json, got %q", ct)
+	}
+}
+
+// jsonFields returns the JSON name of each field of a struct type, following
+// embedded structs the way encoding/json does
+func jsonFields(typ reflect.Type) map[string]reflect.Type {
+	fields := make(map[string]reflect.Type)
+	for i := 0; i < typ.NumField(); i++ {
+		f := typ.Field(i)
+		if f.Anonymous && f.Type.Kind() == reflect.Struct {
+			for name, ft := range jsonFields(f.Type) {
+				fields[name] = ft
+			}
+			continue
+		}
+		if !f.IsExported() {
+			continue
+		}
+		name, _, _ := strings.Cut(f.Tag.Get("json"), ",")
+		if name == "-" {
+			continue
+		}
+		if name == "" {
+			name = f.Name
+		}
+		fields[name] = f.Type
+	}
+	return fields
+}
+
+func checkObjectSchema(t *testing.T, doc openAPIDoc, where string, typ reflect.Type, schema map[string]any) {
+	t.Helper()
+	props, _ := schema["properties"].(map[string]any)
+	fields := jsonFields(typ)
+	for name, ft := range fields {
+		prop, ok := props[name].(map[string]any)
+		if !ok {
+			t.Errorf("%s.%s is missing from openapi.json", where, name)
+			continue
+		}
+		checkSchema(t, doc, where+"."+name, ft, prop)
+	}
+	for name := range props {
+		if _, ok := fields[name]; !ok {
+			t.Errorf("openapi.json describes %s.%s, which has no Go field", where, name)
+		}
+	}
+}
+
+var timeType = reflect.TypeOf(time.Time{})
+
+func checkSchema(t *testing.T, doc openAPIDoc, where string, typ reflect.Type, schema map[string]any) {
+	t.Helper()
+	if ref, ok := schema["$ref"].(string); ok {
+		name := strings.TrimPrefix(ref, "#/components/schemas/")
+		if typ.Kind() == reflect.Struct && typ != timeType {
+			if name != typ.Name() {
+				t.Errorf("%s: expected a reference to %s, got %s", where, typ.Name(), name)
+			}
+			return
+		}
+		schema = doc.Components.Schemas[name]
+	}
+
+	var want string
+	switch typ.Kind() {
+	case reflect.String:
+		want = "string"
+	case reflect.Int, reflect.Int64:
+		want = "integer"
+	case reflect.Float64:
+		want = "number"
+	case reflect.Bool:
+		want = "boolean"
+	case reflect.Slice:
+		want = "array"
+		if items, ok := schema["items"].(map[string]any); ok {
+			checkSchema(t, doc, where+"[]", typ.Elem(), items)
+		} else {
+			t.Errorf("%s: array schema has no items", where)
+		}
+	case reflect.Map:
+		want = "object"
+		if values, ok := schema["additionalProperties"].(map[string]any); ok {
+			checkSchema(t, doc, where+"{}", typ.Elem(), values)
+		} else if typ.Elem().Kind() != reflect.Interface {
+			t.Errorf("%s: map schema has no additionalProperties", where)
+		}
+	case reflect.Struct:
+		if typ == timeType {
+			want = "string"
+		} else {
+			t.Errorf("%s: expected a $ref to %s", where, typ.Name())
+			return
+		}
+	case reflect.Interface:
+		return
+	default:
+		t.Errorf("%s: unsupported Go type %s", where, typ)
+		return
+	}
+	if got := schema["type"]; got != want {
+		t.Errorf("%s: expected type %s for %s, got %v", where, want, typ, got)
+	}
+}
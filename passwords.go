@@ -0,0 +1,72 @@
+package main
+
+import (
+	"crypto/hmac"
+	"crypto/rand"
+	"crypto/sha256"
+	"crypto/subtle"
+	"encoding/binary"
+	"unicode/utf8"
+)
+
+// Room passwords are stored as salted PBKDF2-HMAC-SHA256 hashes
+
+const (
+	passwordSaltSize   = 16
+	passwordIterations = 100_000
+
+	minPasswordLength = 4
+	maxPasswordLength = 64
+)
+
+type passwordHash struct {
+	salt []byte
+	hash []byte
+}
+
+// pbkdf2SHA256 derives a single 32-byte block (RFC 8018, section 5.2)
+func pbkdf2SHA256(password, salt []byte, iterations int) []byte {
+	mac := hmac.New(sha256.New, password)
+
+	var blockIndex [4]byte
+	binary.BigEndian.PutUint32(blockIndex[:], 1)
+	mac.Write(salt)
+	mac.Write(blockIndex[:])
+	u := mac.Sum(nil)
+
+	out := make([]byte, len(u))
+	copy(out, u)
+	for i := 1; i < iterations; i++ {
+		mac.Reset()
+		mac.Write(u)
+		u = mac.Sum(u[:0])
+		for j := range out {
+			out[j] ^= u[j]
+		}
+	}
+	return out
+}
+
+func hashPassword(password string) *passwordHash {
+	salt := make([]byte, passwordSaltSize)
+	rand.Read(salt)
+	return &passwordHash{
+		salt: salt,
+		hash: pbkdf2SHA256([]byte(password), salt, passwordIterations),
+	}
+}
+
+// Matches reports whether password hashes to the stored value. Passwords
+// that could never have been set are rejected without hashing.
+func (p *passwordHash) Matches(password string) bool {
+	if !validPassword(password) {
+		return false
+	}
+	candidate := pbkdf2SHA256([]byte(password), p.salt, passwordIterations)
+	return subtle.ConstantTimeCompare(candidate, p.hash) == 1
+}
+
+func validPassword(password string) bool {
+	n := utf8.RuneCountInString(password)
+	return n >= minPasswordLength && n <= maxPasswordLength
+}
@@ -0,0 +1,224 @@
+package main
+
+import (
+	"math"
+	"net"
+	"net/http"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+)
+
+// Token-bucket rate limiting for the room API. Each budget is tracked per
+// client IP and, for requests that target an existing room, per room.
+
+// RateLimitConfig sets the per-minute budgets. A budget of 0 disables that
+// limit. Each bucket holds up to one minute's worth of tokens.
+type RateLimitConfig struct {
+	CreatePerMinute     int `json:"createPerMinute"`
+	JoinPerMinute       int `json:"joinPerMinute"`
+	ActionPerMinute     int `json:"actionPerMinute"`
+	RoomJoinPerMinute   int `json:"roomJoinPerMinute"`
+	RoomActionPerMinute int `json:"roomActionPerMinute"`
+
+	// Wrong room passwords allowed per client and room
+	PasswordFailuresPerMinute int `json:"passwordFailuresPerMinute"`
+}
+
+// bucketIdleTTL is how long an unused bucket is kept before being swept
+const bucketIdleTTL = 10 * time.Minute
+
+type tokenBucket struct {
+	tokens float64
+	last   time.Time
+}
+
+type rateLimiter struct {
+	rate      float64 // tokens per second
+	burst     float64
+	mu        sync.Mutex
+	buckets   map[string]*tokenBucket
+	lastSweep time.Time
+}
+
+// newRateLimiter returns a limiter allowing perMinute requests per key per
+// minute, or nil if perMinute is 0
+func newRateLimiter(perMinute int) *rateLimiter {
+	if perMinute <= 0 {
+		return nil
+	}
+	return &rateLimiter{
+		rate:    float64(perMinute) / 60,
+		burst:   float64(perMinute),
+		buckets: make(map[string]*tokenBucket),
+	}
+}
+
+// Allow takes a token from key's bucket. If none is available it returns
+// false and how long until one will be.
+func (l *rateLimiter) Allow(key string, now time.Time) (bool, time.Duration) {
+	if l == nil {
+		return true, 0
+	}
+
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	l.sweep(now)
+
+	b, ok := l.buckets[key]
+	if !ok {
+		b = &tokenBucket{tokens: l.burst, last: now}
+		l.buckets[key] = b
+	}
+	l.refill(b, now)
+
+	if b.tokens >= 1 {
+		b.tokens--
+		return true, 0
+	}
+	return false, l.wait(b)
+}
+
+// Blocked reports whether key's bucket is empty without taking a token, and
+// if so how long until it won't be
+func (l *rateLimiter) Blocked(key string, now time.Time) (bool, time.Duration) {
+	if l == nil {
+		return false, 0
+	}
+
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	b, ok := l.buckets[key]
+	if !ok {
+		return false, 0
+	}
+	l.refill(b, now)
+
+	if b.tokens >= 1 {
+		return false, 0
+	}
+	return true, l.wait(b)
+}
+
+func (l *rateLimiter) refill(b *tokenBucket, now time.Time) {
+	b.tokens = math.Min(l.burst, b.tokens+now.Sub(b.last).Seconds()*l.rate)
+	b.last = now
+}
+
+// wait returns how long until b holds a whole token
+func (l *rateLimiter) wait(b *tokenBucket) time.Duration {
+	return time.Duration((1 - b.tokens) / l.rate * float64(time.Second))
+}
+
+// sweep drops buckets that have been idle long enough to be full again
+func (l *rateLimiter) sweep(now time.Time) {
+	if now.Sub(l.lastSweep) < bucketIdleTTL {
+		return
+	}
+	l.lastSweep = now
+	for key, b := range l.buckets {
+		if now.Sub(b.last) > bucketIdleTTL {
+			delete(l.buckets, key)
+		}
+	}
+}
+
+// Active limiters, configured at startup by configureRateLimits. A nil
+// limiter allows everything.
+var (
+	createLimiter     *rateLimiter
+	joinLimiter       *rateLimiter
+	actionLimiter     *rateLimiter
+	roomJoinLimiter   *rateLimiter
+	roomActionLimiter *rateLimiter
+	passwordLimiter   *rateLimiter
+)
+
+func configureRateLimits(c RateLimitConfig) {
+	createLimiter = newRateLimiter(c.CreatePerMinute)
+	joinLimiter = newRateLimiter(c.JoinPerMinute)
+	actionLimiter = newRateLimiter(c.ActionPerMinute)
+	roomJoinLimiter = newRateLimiter(c.RoomJoinPerMinute)
+	roomActionLimiter = newRateLimiter(c.RoomActionPerMinute)
+	passwordLimiter = newRateLimiter(c.PasswordFailuresPerMinute)
+}
+
+// clientIP returns the address of the client making the request. When a
+// trusted proxy header is configured, the right-most address in it (the one
+// added by the proxy itself) is used.
+func clientIP(r *http.Request) string {
+	if header := config.TrustedProxyHeader; header != "" {
+		if value := r.Header.Get(header); value != "" {
+			entries := strings.Split(value, ",")
+			if ip := net.ParseIP(strings.TrimSpace(entries[len(entries)-1])); ip != nil {
+				return ip.String()
+			}
+		}
+	}
+	host, _, err := net.SplitHostPort(r.RemoteAddr)
+	if err != nil {
+		return r.RemoteAddr
+	}
+	return host
+}
+
+// writeRateLimited responds with 429 and a Retry-After header
+func writeRateLimited(w http.ResponseWriter, retryAfter time.Duration) {
+	seconds := int(math.Ceil(retryAfter.Seconds()))
+	if seconds < 1 {
+		seconds = 1
+	}
+	w.Header().Set("Retry-After", strconv.Itoa(seconds))
+	writeError(w, ErrRateLimited.WithDetails(map[string]any{"retryAfterSeconds": seconds}))
+}
+
+// clientLimiter returns the per-client limiter for a kind of request:
+// "create", "join" or "action"
+func clientLimiter(kind string) *rateLimiter {
+	switch kind {
+	case "create":
+		return createLimiter
+	case "join":
+		return joinLimiter
+	case "action":
+		return actionLimiter
+	}
+	return nil
+}
+
+// roomLimiter returns the per-room limiter for a kind of request
+func roomLimiter(kind string) *rateLimiter {
+	switch kind {
+	case "join":
+		return roomJoinLimiter
+	case "action":
+		return roomActionLimiter
+	}
+	return nil
+}
+
+// rateLimited wraps a room handler with the client and room budgets for the
+// given kind of request: "create", "join" or "action". The limiters are
+// looked up per request, so routes may be registered before
+// configureRateLimits runs. Only rooms that exist are charged.
+func rateLimited(kind string, next http.HandlerFunc) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		now := time.Now()
+
+		if ok, wait := clientLimiter(kind).Allow(kind+"|"+clientIP(r), now); !ok {
+			writeRateLimited(w, wait)
+			return
+		}
+		if room, exists := getRoom(r.PathValue("code")); exists {
+			if ok, wait := roomLimiter(kind).Allow(room.RoomCode, now); !ok {
+				writeRateLimited(w, wait)
+				return
+			}
+		}
+
+		next(w, r)
+	}
+}
@@ -0,0 +1,132 @@
+package main
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+)
+
+func TestRateLimiterAllow(t *testing.T) {
+	l := newRateLimiter(2) // 2 per minute, burst of 2
+	now := time.Now()
+
+	for i := 0; i < 2; i++ {
+		if ok, _ := l.Allow("client", now); !ok {
+			t.Fatalf("Expected request %d to be allowed", i+1)
+		}
+	}
+
+	ok, wait := l.Allow("client", now)
+	if ok {
+		t.Fatal("Expected third request to be limited")
+	}
+	if wait <= 0 || wait > 30*time.Second {
+		t.Errorf("Expected a wait of up to 30s, got %v", wait)
+	}
+
+	// Other keys have their own bucket
+	if ok, _ := l.Allow("other", now); !ok {
+		t.Error("Expected a different key to be allowed")
+	}
+
+	// A token refills after 30s at 2 per minute
+	if ok, _ := l.Allow("client", now.Add(30*time.Second)); !ok {
+		t.Error("Expected request to be allowed after refill")
+	}
+}
+
+func TestRateLimiterDisabled(t *testing.T) {
+	l := newRateLimiter(0)
+	if l != nil {
+		t.Fatal("Expected a nil limiter for a zero budget")
+	}
+	if ok, _ := l.Allow("client", time.Now()); !ok {
+		t.Error("Expected a nil limiter to allow everything")
+	}
+}
+
+func TestRateLimitedHandler(t *testing.T) {
+	defer configureRateLimits(RateLimitConfig{})
+	configureRateLimits(RateLimitConfig{JoinPerMinute: 1})
+
+	handler := rateLimited("join", func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusOK)
+	})
+
+	req := httptest.NewRequest(http.MethodPost, "/api/rooms/ROOM/join", nil)
+	rec := httptest.NewRecorder()
+	handler(rec, req)
+	if rec.Code != http.StatusOK {
+		t.Fatalf("Expected 200, got %d", rec.Code)
+	}
+
+	rec = httptest.NewRecorder()
+	handler(rec, req)
+	if rec.Code != http.StatusTooManyRequests {
+		t.Fatalf("Expected 429, got %d", rec.Code)
+	}
+	if rec.Header().Get("Retry-After") == "" {
+		t.Error("Expected a Retry-After header")
+	}
+}
+
+func TestRateLimitsConfiguredAfterRouter(t *testing.T) {
+	ClearRooms()
+	mux := newRouter()
+	defer configureRateLimits(RateLimitConfig{})
+	configureRateLimits(RateLimitConfig{RoomJoinPerMinute: 1})
+
+	// Joining a missing room doesn't use up that code's budget
+	rec := serve(mux, http.MethodPost, "/api/v1/rooms/LIMITED/join", `{"playerName": "Bob"}`)
+	if rec.Code != http.StatusNotFound {
+		t.Fatalf("Expected 404, got %d: %s", rec.Code, rec.Body)
+	}
+
+	CreateRoom("LIMITED", 5, "Alice")
+	rec = serve(mux, http.MethodPost, "/api/v1/rooms/LIMITED/join", `{"playerName": "Bob"}`)
+	if rec.Code != http.StatusOK {
+		t.Fatalf("Expected 200, got %d: %s", rec.Code, rec.Body)
+	}
+	rec = serve(mux, http.MethodPost, "/api/v1/rooms/LIMITED/join", `{"playerName": "Carol"}`)
+	if rec.Code != http.StatusTooManyRequests {
+		t.Errorf("Expected 429 from a limit configured after the router, got %d", rec.Code)
+	}
+}
+
+func TestBotChangesUseJoinBudget(t *testing.T) {
+	ClearRooms()
+	mux := newRouter()
+	defer configureRateLimits(RateLimitConfig{})
+	configureRateLimits(RateLimitConfig{JoinPerMinute: 1})
+	CreateRoomWithOptions("BOTLIMIT", "Alice", RoomOptions{GridSize: 5, Password: "sesame"})
+
+	// Adding and removing bots checks the password, so both draw on the
+	// join budget before any hashing
+	rec := serve(mux, http.MethodPost, "/api/v1/rooms/BOTLIMIT/bots", `{"playerName": "Alice", "password": "wrong"}`)
+	if rec.Code != http.StatusForbidden {
+		t.Fatalf("Expected 403, got %d: %s", rec.Code, rec.Body)
+	}
+	rec = serve(mux, http.MethodDelete, "/api/v1/rooms/BOTLIMIT/bots/Bot%201", `{"playerName": "Alice", "password": "sesame"}`)
+	if rec.Code != http.StatusTooManyRequests {
+		t.Errorf("Expected 429, got %d: %s", rec.Code, rec.Body)
+	}
+}
+
+func TestClientIP(t *testing.T) {
+	defer func(header string) { config.TrustedProxyHeader = header }(config.TrustedProxyHeader)
+
+	req := httptest.NewRequest(http.MethodGet, "/", nil)
+	req.RemoteAddr = "10.0.0.1:1234"
+	req.Header.Set("X-Forwarded-For", "1.2.3.4, 5.6.7.8")
+
+	config.TrustedProxyHeader = ""
+	if ip := clientIP(req); ip != "10.0.0.1" {
+		t.Errorf("Expected remote address without a trusted header, got %s", ip)
+	}
+
+	config.TrustedProxyHeader = "X-Forwarded-For"
+	if ip := clientIP(req); ip != "5.6.7.8" {
+		t.Errorf("Expected right-most forwarded address, got %s", ip)
+	}
+}
@@ -0,0 +1,87 @@
+package main
+
+import (
+	"math/rand"
+	"strings"
+)
+
+// Room codes are case-insensitive and stored upper-case. Server-generated
+// codes alternate consonants and vowels so they are easy to read aloud, and
+// leave out letters that are easily confused with digits or each other
+// (I, L, O, Q, Y).
+
+const (
+	codeConsonants = "BCDFGHJKMNPRSTVWXZ"
+	codeVowels     = "AEU"
+
+	generatedCodeLength  = 6
+	generateCodeAttempts = 50
+
+	minRoomCodeLength = 3
+	maxRoomCodeLength = 24
+)
+
+// blockedCodeFragments are substrings that must not appear in a generated
+// code. Only consonant-vowel patterns matter since that's all we generate.
+var blockedCodeFragments = []string{
+	"CUM", "FAG", "FUC", "FUK", "FUX", "KUM", "NAZ",
+	"PUTA", "PUTE", "RAPE", "SEX", "SUC", "SUK", "VAG",
+}
+
+// normalizeRoomCode returns the canonical form of a room code for storage
+// and lookup
+func normalizeRoomCode(code string) string {
+	return strings.ToUpper(strings.TrimSpace(code))
+}
+
+// validRoomCode reports whether a normalized code is an acceptable
+// client-supplied room code: 3-24 characters of A-Z, 0-9, '-' or '_'
+func validRoomCode(code string) bool {
+	if len(code) < minRoomCodeLength || len(code) > maxRoomCodeLength {
+		return false
+	}
+	for _, c := range code {
+		switch {
+		case c >= 'A' && c <= 'Z', c >= '0' && c <= '9', c == '-', c == '_':
+		default:
+			return false
+		}
+	}
+	return true
+}
+
+func blockedRoomCode(code string) bool {
+	for _, fragment := range blockedCodeFragments {
+		if strings.Contains(code, fragment) {
+			return true
+		}
+	}
+	return false
+}
+
+func randomRoomCode() string {
+	b := make([]byte, generatedCodeLength)
+	for i := range b {
+		if i%2 == 0 {
+			b[i] = codeConsonants[rand.Intn(len(codeConsonants))]
+		} else {
+			b[i] = codeVowels[rand.Intn(len(codeVowels))]
+		}
+	}
+	return string(b)
+}
+
+// generateRoomCode returns an unused, inoffensive room code, or "" if none
+// was found. The caller must hold roomsMu.
+func generateRoomCode() string {
+	for i := 0; i < generateCodeAttempts; i++ {
+		code := randomRoomCode()
+		if blockedRoomCode(code) {
+			continue
+		}
+		if _, exists := rooms[code]; !exists {
+			return code
+		}
+	}
+	return ""
+}
@@ -0,0 +1,102 @@
+package main
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestGeneratedRoomCodes(t *testing.T) {
+	ClearRooms()
+
+	for i := 0; i < 500; i++ {
+		room, err := CreateRoom("", 3, "Alice")
+		if err != nil {
+			t.Fatalf("Expected no error, got %v", err)
+		}
+		code := room.RoomCode
+		if len(code) != generatedCodeLength {
+			t.Fatalf("Expected a %d character code, got %q", generatedCodeLength, code)
+		}
+		for j, c := range code {
+			alphabet := codeConsonants
+			if j%2 == 1 {
+				alphabet = codeVowels
+			}
+			if !strings.ContainsRune(alphabet, c) {
+				t.Fatalf("Unexpected character %q in code %q", c, code)
+			}
+		}
+		if blockedRoomCode(code) {
+			t.Fatalf("Generated blocked code %q", code)
+		}
+	}
+
+	if len(ListRoomSummaries()) != 500 {
+		t.Error("Expected every generated code to be unique")
+	}
+}
+
+func TestRoomCodeCaseInsensitive(t *testing.T) {
+	ClearRooms()
+
+	room, err := CreateRoom(" myRoom ", 5, "Alice")
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	if room.RoomCode != "MYROOM" {
+		t.Errorf("Expected normalized code MYROOM, got %q", room.RoomCode)
+	}
+
+	if _, err := JoinRoom("myroom", "Bob"); err != nil {
+		t.Errorf("Expected lower-case code to find the room, got %v", err)
+	}
+
+	_, err = CreateRoom("MyRoom", 5, "Charlie")
+	if err != ErrRoomExists {
+		t.Errorf("Expected ErrRoomExists, got %v", err)
+	}
+}
+
+func TestInvalidRoomCodes(t *testing.T) {
+	ClearRooms()
+
+	for _, code := range []string{"AB", "ROOM/JOIN", "ROOM CODE", "ÄBC", strings.Repeat("X", 25)} {
+		if _, err := CreateRoom(code, 5, "Alice"); err != ErrInvalidRoomCode {
+			t.Errorf("CreateRoom(%q): expected ErrInvalidRoomCode, got %v", code, err)
+		}
+	}
+}
+
+func TestRoomCodesExhausted(t *testing.T) {
+	ClearRooms()
+	defer ClearRooms()
+
+	// Take every code the generator can produce
+	roomsMu.Lock()
+	codes := []string{""}
+	for i := 0; i < generatedCodeLength; i++ {
+		alphabet := codeConsonants
+		if i%2 == 1 {
+			alphabet = codeVowels
+		}
+		var longer []string
+		for _, code := range codes {
+			for _, c := range alphabet {
+				longer = append(longer, code+string(c))
+			}
+		}
+		codes = longer
+	}
+	for _, code := range codes {
+		rooms[code] = &Room{RoomCode: code}
+	}
+	roomsMu.Unlock()
+
+	if _, err := CreateRoom("", 3, "Alice"); err != ErrNoFreeRoomCode {
+		t.Errorf("Expected ErrNoFreeRoomCode, got %v", err)
+	}
+	// A chosen code still works
+	if _, err := CreateRoom("MINE", 3, "Alice"); err != nil {
+		t.Errorf("Expected no error, got %v", err)
+	}
+}
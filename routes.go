@@ -0,0 +1,148 @@
+package main
+
+import (
+	"net/http"
+	"sort"
+	"strings"
+)
+
+// apiPrefixes are the mount points of the JSON API. /api/v1 is the current
+// version; the unversioned /api paths are kept for existing clients.
+var apiPrefixes = []string{"/api/v1", legacyAPIPrefix}
+
+const legacyAPIPrefix = "/api"
+
+// legacyAliases are extra paths the unversioned API answered before it was
+// routed by pattern, keyed by the path of the route they stand for
+var legacyAliases = map[string]string{
+	"/rooms": "/rooms/{$}", // POST /api/rooms/ created a room
+}
+
+// route is one endpoint, relative to an API prefix
+type route struct {
+	method  string
+	path    string
+	name    string // handler label used in metrics
+	limit   string // rate limit kind, see rateLimited; "" for none
+	handler http.HandlerFunc
+}
+
+var apiRoutes = []route{
+	{http.MethodPost, "/rooms", "create_room", "create", handleCreateRoom},
+	{http.MethodPost, "/rooms/{code}/join", "join", "join", handleJoinRoom},
+	{http.MethodPost, "/rooms/{code}/leave", "leave", "action", handleLeaveRoom},
+	{http.MethodPost, "/rooms/{code}/start", "start", "action", handleStartGame},
+	{http.MethodPost, "/rooms/{code}/guess", "guess", "action", handleGuess},
+	{http.MethodGet, "/rooms/{code}/state", "state", "", handleGetState},
+	{http.MethodGet, "/rooms/{code}/history", "history", "action", handleGetHistory},
+	{http.MethodGet, "/rooms/{code}/share", "share", "action", handleGetShare},
+	{http.MethodGet, "/rooms/{code}/clue", "suggest_clue", "action", handleSuggestClue},
+	{http.MethodGet, "/rooms/{code}/matches", "match_clue", "action", handleMatchClue},
+	{http.MethodPost, "/rooms/{code}/bots", "add_bot", "join", handleAddBot},
+	{http.MethodDelete, "/rooms/{code}/bots/{name}", "remove_bot", "join", handleRemoveBot},
+	{http.MethodGet, "/lobby", "lobby", "", handleGetLobby},
+	{http.MethodPost, "/lobby/quick-join", "quick_join", "join", handleQuickJoin},
+}
+
+var adminRoutes = []route{
+	{method: http.MethodGet, path: "/admin/rooms", handler: handleAdminListRooms},
+	{method: http.MethodGet, path: "/admin/rooms/{code}", handler: handleAdminGetRoom},
+	{method: http.MethodDelete, path: "/admin/rooms/{code}", handler: handleAdminDeleteRoom},
+	{method: http.MethodPost, path: "/admin/rooms/{code}/end", handler: handleAdminEndGame},
+	{method: http.MethodDelete, path: "/admin/rooms/{code}/players/{name}", handler: handleAdminRemovePlayer},
+}
+
+// registerAPIRoutes mounts the JSON API under each of apiPrefixes. Known
+// paths requested with the wrong method get a 405 listing the allowed
+// methods, and unknown paths get a JSON 404.
+func registerAPIRoutes(mux *http.ServeMux) {
+	for _, prefix := range apiPrefixes {
+		allowed := make(map[string][]string)
+		var paths []string
+
+		for _, rt := range apiRoutes {
+			handler, name := rt.handler, rt.name
+			if rt.limit != "" {
+				handler = rateLimited(rt.limit, handler)
+			}
+			pattern := prefix + rt.path
+			wrapped := accessLog(pattern, enableCORS(func(w http.ResponseWriter, r *http.Request) {
+				observe(w, r, name, handler)
+			}))
+			mux.HandleFunc(rt.method+" "+pattern, wrapped)
+
+			if allowed[rt.path] == nil {
+				paths = append(paths, rt.path)
+			}
+			allowed[rt.path] = append(allowed[rt.path], rt.method)
+
+			if alias, ok := legacyAliases[rt.path]; ok && prefix == legacyAPIPrefix {
+				mux.HandleFunc(rt.method+" "+prefix+alias, wrapped)
+				if allowed[alias] == nil {
+					paths = append(paths, alias)
+				}
+				allowed[alias] = append(allowed[alias], rt.method)
+			}
+		}
+
+		for _, rt := range adminRoutes {
+			pattern := prefix + rt.path
+			mux.HandleFunc(rt.method+" "+pattern, accessLog(pattern, requireAdmin(rt.handler)))
+
+			if allowed[rt.path] == nil {
+				paths = append(paths, rt.path)
+			}
+			allowed[rt.path] = append(allowed[rt.path], rt.method)
+		}
+
+		for _, path := range paths {
+			pattern := prefix + path
+			handler := methodNotAllowed(allowed[path])
+			if strings.HasPrefix(path, "/admin/") {
+				handler = requireAdmin(handler)
+			} else {
+				// Preflight requests land here and are answered by enableCORS
+				handler = enableCORS(handler)
+			}
+			mux.HandleFunc(pattern, accessLog(pattern, handler))
+		}
+
+		mux.HandleFunc("GET "+prefix+"/openapi.json", enableCORS(handleOpenAPI))
+
+		mux.HandleFunc(prefix+"/", accessLog(prefix+"/", enableCORS(func(w http.ResponseWriter, r *http.Request) {
+			writeError(w, ErrEndpointNotFound)
+		})))
+	}
+}
+
+// methodNotAllowed responds with 405 and an Allow header listing methods
+func methodNotAllowed(methods []string) http.HandlerFunc {
+	allow := append([]string(nil), methods...)
+	for _, method := range methods {
+		if method == http.MethodGet {
+			allow = append(allow, http.MethodHead)
+		}
+	}
+	sort.Strings(allow)
+	header := strings.Join(allow, ", ")
+
+	return func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Allow", header)
+		writeError(w, ErrMethodNotAllowed)
+	}
+}
+
+// newRouter returns a mux with the API, admin, health and metrics routes
+func newRouter() *http.ServeMux {
+	mux := http.NewServeMux()
+	registerAPIRoutes(mux)
+
+	// Health checks
+	mux.HandleFunc("GET /healthz", handleHealthz)
+	mux.HandleFunc("GET /readyz", handleReadyz)
+
+	// Prometheus metrics
+	mux.HandleFunc("GET /metrics", handleMetrics)
+
+	return mux
+}
@@ -0,0 +1,105 @@
+package main
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+)
+
+func serve(mux *http.ServeMux, method, path, body string) *httptest.ResponseRecorder {
+	req := httptest.NewRequest(method, path, strings.NewReader(body))
+	rec := httptest.NewRecorder()
+	mux.ServeHTTP(rec, req)
+	return rec
+}
+
+func TestVersionedAndLegacyRoutes(t *testing.T) {
+	ClearRooms()
+	mux := newRouter()
+
+	rec := serve(mux, http.MethodPost, "/api/v1/rooms", `{"playerName": "Alice"}`)
+	if rec.Code != http.StatusCreated {
+		t.Fatalf("Expected 201, got %d: %s", rec.Code, rec.Body)
+	}
+	var created CreateRoomResponse
+	json.NewDecoder(rec.Body).Decode(&created)
+
+	// The same room is reachable through the legacy paths
+	rec = serve(mux, http.MethodPost, "/api/rooms/"+strings.ToLower(created.RoomCode)+"/join", `{"playerName": "Bob"}`)
+	if rec.Code != http.StatusOK {
+		t.Fatalf("Expected 200, got %d: %s", rec.Code, rec.Body)
+	}
+
+	rec = serve(mux, http.MethodGet, "/api/v1/rooms/"+created.RoomCode+"/state?playerName=Bob", "")
+	if rec.Code != http.StatusOK {
+		t.Errorf("Expected 200, got %d: %s", rec.Code, rec.Body)
+	}
+}
+
+func TestMethodNotAllowed(t *testing.T) {
+	mux := newRouter()
+
+	rec := serve(mux, http.MethodGet, "/api/v1/rooms/ABC/join", "")
+	if rec.Code != http.StatusMethodNotAllowed {
+		t.Fatalf("Expected 405, got %d", rec.Code)
+	}
+	if allow := rec.Header().Get("Allow"); allow != "POST" {
+		t.Errorf("Expected Allow: POST, got %q", allow)
+	}
+	var resp ErrorResponse
+	json.NewDecoder(rec.Body).Decode(&resp)
+	if resp.Code != CodeMethodNotAllowed {
+		t.Errorf("Expected code %s, got %s", CodeMethodNotAllowed, resp.Code)
+	}
+
+	rec = serve(mux, http.MethodPost, "/api/rooms/ABC/state", "")
+	if allow := rec.Header().Get("Allow"); rec.Code != http.StatusMethodNotAllowed || allow != "GET, HEAD" {
+		t.Errorf("Expected 405 with Allow: GET, HEAD, got %d with %q", rec.Code, allow)
+	}
+
+	// Preflight requests are answered rather than rejected
+	rec = serve(mux, http.MethodOptions, "/api/v1/rooms/ABC/join", "")
+	if rec.Code != http.StatusOK {
+		t.Errorf("Expected 200 for a preflight request, got %d", rec.Code)
+	}
+}
+
+func TestUnknownRoute(t *testing.T) {
+	mux := newRouter()
+
+	for _, path := range []string{"/api/v1/nope", "/api/rooms/ABC/dance", "/api/v1/rooms/ABC/join/extra"} {
+		rec := serve(mux, http.MethodPost, path, "")
+		if rec.Code != http.StatusNotFound {
+			t.Errorf("%s: expected 404, got %d", path, rec.Code)
+			continue
+		}
+		var resp ErrorResponse
+		json.NewDecoder(rec.Body).Decode(&resp)
+		if resp.Code != CodeNotFound {
+			t.Errorf("%s: expected code %s, got %s", path, CodeNotFound, resp.Code)
+		}
+	}
+}
+
+func TestLegacyTrailingSlash(t *testing.T) {
+	ClearRooms()
+	mux := newRouter()
+
+	// Clients of the unversioned API created rooms with a trailing slash
+	rec := serve(mux, http.MethodPost, "/api/rooms/", `{"playerName": "Alice"}`)
+	if rec.Code != http.StatusCreated {
+		t.Fatalf("Expected 201, got %d: %s", rec.Code, rec.Body)
+	}
+	rec = serve(mux, http.MethodGet, "/api/rooms/", "")
+	if allow := rec.Header().Get("Allow"); rec.Code != http.StatusMethodNotAllowed || allow != "POST" {
+		t.Errorf("Expected 405 with Allow: POST, got %d with %q", rec.Code, allow)
+	}
+
+	// The alias is not part of the versioned API
+	rec = serve(mux, http.MethodPost, "/api/v1/rooms/", `{"playerName": "Alice"}`)
+	if rec.Code != http.StatusNotFound {
+		t.Errorf("Expected 404 under /api/v1, got %d", rec.Code)
+	}
+}
@@ -1,8 +1,11 @@
 package main
 
-import "sync"
+import (
+	"sync"
+	"time"
+)
 
-// Game configuration defaults
+// Game configuration defaults, overridable at runtime (see config.go)
 const DefaultGridSize = 5
 const MinGridSize = 3
 const MaxGridSize = 5
@@ -27,6 +30,9 @@ type CellResponse struct {
 type Room struct {
 	RoomCode    string            `json:"roomCode"`
 	GridSize    int               `json:"gridSize"`
+	WordPack    string            `json:"wordPack"`
+	Public      bool              `json:"public"`
+	MaxPlayers  int               `json:"maxPlayers"` // 0 means unlimited
 	Players     []string          `json:"players"`
 	GameStarted bool              `json:"gameStarted"`
 	GameOver    bool              `json:"gameOver"`
@@ -35,14 +41,23 @@ type Room struct {
 	Grid        [][]Cell          `json:"-"`
 	CardDeck    []Card            `json:"-"`
 	PlayerHands map[string][]Card `json:"-"`
+	CreatedAt   time.Time         `json:"createdAt"`
+	StartedAt   time.Time         `json:"-"` // When the current game started
+	History     []GameRecord      `json:"-"` // Finished games, oldest first
+	gamesPlayed int               // Finished games, including those dropped from History
+	password    *passwordHash     // nil unless the room is private
 	mu          sync.RWMutex
 }
 
 // Request/Response types
 
 type CreateRoomRequest struct {
-	RoomCode   string `json:"roomCode"`
+	RoomCode   string `json:"roomCode,omitempty"` // Optional; generated by the server if empty
 	GridSize   int    `json:"gridSize"`
+	WordPack   string `json:"wordPack,omitempty"`
+	Password   string `json:"password,omitempty"` // Optional; makes the room private
+	Public     bool   `json:"public,omitempty"`   // List the room in the lobby
+	MaxPlayers int    `json:"maxPlayers,omitempty"`
 	PlayerName string `json:"playerName"`
 }
 
@@ -54,6 +69,7 @@ type CreateRoomResponse struct {
 
 type JoinRoomRequest struct {
 	PlayerName string `json:"playerName"`
+	Password   string `json:"password,omitempty"`
 }
 
 type JoinRoomResponse struct {
@@ -68,6 +84,13 @@ type StartGameResponse struct {
 	Message  string `json:"message"`
 }
 
+// RoomMessageResponse acknowledges an action on a room that has no other
+// result, such as leaving it
+type RoomMessageResponse struct {
+	RoomCode string `json:"roomCode"`
+	Message  string `json:"message"`
+}
+
 type GuessRequest struct {
 	PlayerName string `json:"playerName"`
 	Row        int    `json:"row"`
@@ -93,8 +116,158 @@ type GameStateResponse struct {
 	PlayerCards    []Card           `json:"playerCards"`
 	Grid           [][]CellResponse `json:"grid"`
 	Players        []string         `json:"players"`
+	Share          string           `json:"share,omitempty"` // Shareable summary, once the game is over
+}
+
+type ClueResponse struct {
+	RoomCode     string   `json:"roomCode"`
+	Row          int      `json:"row"`
+	Column       int      `json:"column"`
+	RowWord      string   `json:"rowWord"`
+	ColumnWord   string   `json:"columnWord"`
+	Clue         string   `json:"clue"`
+	Alternatives []string `json:"alternatives"` // Runner-up clues, best first
+}
+
+type CellMatch struct {
+	Row        int     `json:"row"`
+	Column     int     `json:"column"`
+	RowWord    string  `json:"rowWord"`
+	ColumnWord string  `json:"columnWord"`
+	Score      float64 `json:"score"` // 0 to 1
+	Resolved   bool    `json:"resolved,omitempty"`
+}
+
+type ClueMatchResponse struct {
+	RoomCode string      `json:"roomCode"`
+	Clue     string      `json:"clue"`
+	Cells    []CellMatch `json:"cells"` // Best fit first
+}
+
+type CellRecord struct {
+	Row         int    `json:"row"`
+	Column      int    `json:"column"`
+	RowWord     string `json:"rowWord"`
+	ColumnWord  string `json:"columnWord"`
+	Outcome     string `json:"outcome"` // guessed, discarded or unresolved
+	DiscardedBy string `json:"discardedBy,omitempty"`
+}
+
+// GameRecord is a finished game, kept after the room moves on to the next one
+type GameRecord struct {
+	Game        int          `json:"game"` // 1 for the room's first finished game
+	RoomCode    string       `json:"roomCode"`
+	GridSize    int          `json:"gridSize"`
+	WordPack    string       `json:"wordPack"`
+	Players     []string     `json:"players"` // Players in the room when the game ended
+	RowWords    []string     `json:"rowWords"`
+	ColumnWords []string     `json:"columnWords"`
+	Cells       []CellRecord `json:"cells"` // Row by row
+	Score       int          `json:"score"` // Cells guessed correctly
+	TotalCells  int          `json:"totalCells"`
+	EndedEarly  bool         `json:"endedEarly,omitempty"` // Ended before every cell was resolved
+	StartedAt   time.Time    `json:"startedAt"`
+	EndedAt     time.Time    `json:"endedAt"`
+}
+
+type GameHistoryResponse struct {
+	RoomCode string       `json:"roomCode"`
+	Games    []GameRecord `json:"games"` // Oldest first
+}
+
+// ShareResponse is a spoiler-free summary of a finished game for pasting
+// into chat
+type ShareResponse struct {
+	RoomCode string `json:"roomCode"`
+	Game     int    `json:"game"` // As numbered in the room's history
+	Text     string `json:"text"`
+	Link     string `json:"link"` // Back to the room, also the last line of Text
+}
+
+type AddBotRequest struct {
+	PlayerName string `json:"playerName"`         // A player seated in the room
+	Password   string `json:"password,omitempty"` // Required for private rooms
+	Strategy   string `json:"strategy,omitempty"` // random (default), discard, scripted or clue
+	Script     []bool `json:"script,omitempty"`   // Outcomes for the scripted strategy
+	ThinkMs    int    `json:"thinkMs,omitempty"`  // Average delay before each move
+}
+
+type AddBotResponse struct {
+	RoomCode string `json:"roomCode"`
+	BotName  string `json:"botName"`
+	Strategy string `json:"strategy"`
+}
+
+type RemoveBotRequest struct {
+	PlayerName string `json:"playerName"`         // A player seated in the room
+	Password   string `json:"password,omitempty"` // Required for private rooms
+}
+
+type LobbyRoom struct {
+	RoomCode    string `json:"roomCode"`
+	PlayerCount int    `json:"playerCount"`
+	MaxPlayers  int    `json:"maxPlayers"` // 0 means unlimited
+	GridSize    int    `json:"gridSize"`
+	WordPack    string `json:"wordPack"`
+	Private     bool   `json:"private"`
+	AgeSeconds  int64  `json:"ageSeconds"`
+}
+
+type LobbyResponse struct {
+	Rooms []LobbyRoom `json:"rooms"`
+}
+
+type QuickJoinRequest struct {
+	PlayerName string `json:"playerName"`
+	GridSize   int    `json:"gridSize,omitempty"`
+	WordPack   string `json:"wordPack,omitempty"`
+}
+
+type QuickJoinResponse struct {
+	RoomCode   string `json:"roomCode"`
+	PlayerName string `json:"playerName"`
+	CardsDealt int    `json:"cardsDealt"`
+	Created    bool   `json:"created"`
+	Message    string `json:"message"`
 }
 
+// ErrorResponse is the body of every error response. Code is one of the
+// stable codes in errors.go; Error is a human-readable message.
 type ErrorResponse struct {
-	Error string `json:"error"`
+	Error     string         `json:"error"`
+	Code      string         `json:"code"`
+	Details   map[string]any `json:"details,omitempty"`
+	RequestID string         `json:"requestId,omitempty"`
+}
+
+// Admin types
+
+type AdminRoomSummary struct {
+	RoomCode    string    `json:"roomCode"`
+	Players     []string  `json:"players"`
+	GridSize    int       `json:"gridSize"`
+	GameStarted bool      `json:"gameStarted"`
+	GameOver    bool      `json:"gameOver"`
+	Public      bool      `json:"public"`
+	Private     bool      `json:"private"`
+	CreatedAt   time.Time `json:"createdAt"`
+	AgeSeconds  int64     `json:"ageSeconds"`
+}
+
+type AdminRoomListResponse struct {
+	Rooms []AdminRoomSummary `json:"rooms"`
+}
+
+type AdminCell struct {
+	GuessedCorrectly bool   `json:"guessedCorrectly"`
+	DiscardedBy      string `json:"discardedBy"`
+}
+
+type AdminRoomDetail struct {
+	AdminRoomSummary
+	RowWords    []string          `json:"rowWords"`
+	ColumnWords []string          `json:"columnWords"`
+	Grid        [][]AdminCell     `json:"grid"`
+	CardDeck    []Card            `json:"cardDeck"`
+	PlayerHands map[string][]Card `json:"playerHands"`
 }
@@ -0,0 +1,139 @@
+package main
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"net/url"
+	"strings"
+	"time"
+)
+
+// A finished game can be shared as a few lines of text, in the manner of
+// Wordle: the grid as emoji, the score and a link back to the room. It
+// leaves out the words, so it gives nothing away to anyone yet to play the
+// same board.
+
+// shareEmoji marks each cell outcome in a shared grid
+var shareEmoji = map[string]string{
+	outcomeGuessed:    "✅",
+	outcomeDiscarded:  "❌",
+	outcomeUnresolved: "⬜",
+}
+
+// shareText renders a finished game, e.g.
+//
+//	CrossClues 2026-10-18
+//	7/9 on a 3×3 grid
+//	✅✅❌
+//	✅❌✅
+//	✅✅✅
+//	https://crossclues.example/?room=LUNCH
+func shareText(game GameRecord, link string) string {
+	var b strings.Builder
+	fmt.Fprintf(&b, "CrossClues %s\n", game.EndedAt.Format("2006-01-02"))
+	fmt.Fprintf(&b, "%d/%d on a %d×%d grid", game.Score, game.TotalCells, game.GridSize, game.GridSize)
+	if game.EndedEarly {
+		b.WriteString(", ended early")
+	}
+	b.WriteString("\n")
+	for i, cell := range game.Cells {
+		b.WriteString(shareEmoji[cell.Outcome])
+		if (i+1)%game.GridSize == 0 {
+			b.WriteString("\n")
+		}
+	}
+	b.WriteString(link)
+	return b.String()
+}
+
+// roomLink returns the address of the web client's lobby with the room code
+// filled in
+func roomLink(baseURL, roomCode string) string {
+	return baseURL + "/?room=" + url.QueryEscape(roomCode)
+}
+
+// GetShare returns the shareable summary of the last game finished in a
+// room. baseURL is where the web client is served, without a trailing slash.
+// playerName must be seated in the room, and password is needed if the room
+// is private.
+func GetShare(roomCode, playerName, password, baseURL string) (*ShareResponse, error) {
+	room, exists := getRoom(roomCode)
+	if !exists {
+		return nil, ErrRoomNotFound
+	}
+	if err := checkSeated(room, playerName, password); err != nil {
+		return nil, err
+	}
+	return lastShare(roomCode, baseURL)
+}
+
+// lastShare is GetShare for callers that have already checked the player,
+// such as the game state
+func lastShare(roomCode, baseURL string) (*ShareResponse, error) {
+	room, exists := getRoom(roomCode)
+	if !exists {
+		return nil, ErrRoomNotFound
+	}
+
+	room.mu.RLock()
+	defer room.mu.RUnlock()
+
+	if len(room.History) == 0 {
+		return nil, ErrNoFinishedGame
+	}
+	game := room.History[len(room.History)-1]
+	link := roomLink(baseURL, room.RoomCode)
+	return &ShareResponse{
+		RoomCode: room.RoomCode,
+		Game:     game.Game,
+		Text:     shareText(game, link),
+		Link:     link,
+	}, nil
+}
+
+// publicBaseURL returns the address clients reach the server at, for links
+// back to it. Without a configured public URL, it is taken from the request,
+// which is wrong behind a proxy that terminates TLS or rewrites the host.
+func publicBaseURL(r *http.Request) string {
+	if config.PublicURL != "" {
+		return strings.TrimRight(config.PublicURL, "/")
+	}
+	scheme := "http"
+	if r.TLS != nil {
+		scheme = "https"
+	}
+	return scheme + "://" + r.Host
+}
+
+// HTTP Handlers
+
+func handleGetShare(w http.ResponseWriter, r *http.Request) {
+	roomCode := normalizeRoomCode(r.PathValue("code"))
+
+	query := r.URL.Query()
+	playerName := query.Get("playerName")
+	if playerName == "" {
+		writeError(w, ErrPlayerNameRequired)
+		return
+	}
+	logPlayer(r, playerName)
+
+	// Wrong passwords count against the same limit as joins
+	attemptKey := roomCode + "|" + clientIP(r)
+	if blocked, wait := passwordLimiter.Blocked(attemptKey, time.Now()); blocked {
+		writeRateLimited(w, wait)
+		return
+	}
+
+	share, err := GetShare(roomCode, playerName, query.Get("password"), publicBaseURL(r))
+	if err != nil {
+		if errors.Is(err, ErrWrongPassword) {
+			passwordLimiter.Allow(attemptKey, time.Now())
+		}
+		writeError(w, err)
+		return
+	}
+
+	writeJSON(w, http.StatusOK, share)
+}
@@ -0,0 +1,154 @@
+package main
+
+import (
+	"crypto/tls"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestShareText(t *testing.T) {
+	game := GameRecord{
+		GridSize:   3,
+		TotalCells: 9,
+		Score:      6,
+		EndedAt:    time.Date(2026, 10, 18, 12, 30, 0, 0, time.Local),
+	}
+	for i, outcome := range []string{"g", "g", "d", "g", "u", "g", "g", "d", "g"} {
+		cell := CellRecord{Row: i / 3, Column: i % 3, RowWord: "SECRET", Outcome: outcomeGuessed}
+		switch outcome {
+		case "d":
+			cell.Outcome = outcomeDiscarded
+		case "u":
+			cell.Outcome = outcomeUnresolved
+			game.EndedEarly = true
+		}
+		game.Cells = append(game.Cells, cell)
+	}
+
+	want := "CrossClues 2026-10-18\n" +
+		"6/9 on a 3×3 grid, ended early\n" +
+		"✅✅❌\n" +
+		"✅⬜✅\n" +
+		"✅❌✅\n" +
+		"https://crossclues.example/?room=LUNCH"
+	got := shareText(game, "https://crossclues.example/?room=LUNCH")
+	if got != want {
+		t.Errorf("Unexpected summary:\n%s\nwant:\n%s", got, want)
+	}
+	if strings.Contains(got, "SECRET") {
+		t.Error("Expected the summary to leave out the words")
+	}
+}
+
+func TestGetShare(t *testing.T) {
+	ClearRooms()
+	CreateRoom("SHARE", 3, "Alice")
+	JoinRoom("SHARE", "Bob")
+
+	if _, err := GetShare("SHARE", "Alice", "", "http://example.com"); err != ErrNoFinishedGame {
+		t.Errorf("Expected ErrNoFinishedGame before a game, got %v", err)
+	}
+	if _, err := GetShare("NOPE", "Alice", "", "http://example.com"); err != ErrRoomNotFound {
+		t.Errorf("Expected ErrRoomNotFound, got %v", err)
+	}
+
+	StartGame("SHARE")
+	if state, _ := GetGameState("SHARE", "Alice"); state.Share != "" {
+		t.Errorf("Expected no summary in the state of a running game, got %q", state.Share)
+	}
+	finishGame(t, "SHARE")
+
+	share, err := GetShare("share", "Alice", "", "http://example.com")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if share.RoomCode != "SHARE" || share.Game != 1 || share.Link != "http://example.com/?room=SHARE" {
+		t.Errorf("Unexpected summary %+v", share)
+	}
+	lines := strings.Split(share.Text, "\n")
+	if len(lines) != 6 || lines[5] != share.Link {
+		t.Errorf("Expected a header, a score, 3 grid rows and the link, got:\n%s", share.Text)
+	}
+	history, _ := GetHistory("SHARE", "Alice", "")
+	if got := strings.Count(share.Text, "✅"); got != history.Games[0].Score {
+		t.Errorf("Expected %d ticks, got %d", history.Games[0].Score, got)
+	}
+
+	// The summary stays available after the next game starts
+	StartGame("SHARE")
+	if again, err := GetShare("SHARE", "Alice", "", "http://example.com"); err != nil || again.Text != share.Text {
+		t.Errorf("Expected the last finished game to be shared during the next one, got %v", err)
+	}
+}
+
+func TestPublicBaseURL(t *testing.T) {
+	defer func(url string) { config.PublicURL = url }(config.PublicURL)
+	config.PublicURL = ""
+
+	req := httptest.NewRequest(http.MethodGet, "/api/v1/rooms/A/share", nil)
+	req.Host = "games.example:8080"
+	if got := publicBaseURL(req); got != "http://games.example:8080" {
+		t.Errorf("Expected the request host, got %s", got)
+	}
+	req.TLS = &tls.ConnectionState{}
+	if got := publicBaseURL(req); got != "https://games.example:8080" {
+		t.Errorf("Expected https for a TLS request, got %s", got)
+	}
+
+	config.PublicURL = "https://crossclues.example/"
+	if got := publicBaseURL(req); got != "https://crossclues.example" {
+		t.Errorf("Expected the configured URL without its trailing slash, got %s", got)
+	}
+}
+
+func TestShareHandler(t *testing.T) {
+	ClearRooms()
+	defer func(url string) { config.PublicURL = url }(config.PublicURL)
+	config.PublicURL = "https://crossclues.example"
+	CreateRoom("LUNCH", 3, "Alice")
+	JoinRoom("LUNCH", "Bob")
+	mux := newRouter()
+
+	rec := serve(mux, http.MethodGet, "/api/v1/rooms/lunch/share?playerName=alice", "")
+	if rec.Code != http.StatusNotFound || !strings.Contains(rec.Body.String(), CodeNoFinishedGame) {
+		t.Errorf("Expected 404 %s before a game, got %d: %s", CodeNoFinishedGame, rec.Code, rec.Body)
+	}
+
+	StartGame("LUNCH")
+	finishGame(t, "LUNCH")
+
+	rec = serve(mux, http.MethodGet, "/api/v1/rooms/lunch/share?playerName=alice", "")
+	var share ShareResponse
+	json.NewDecoder(rec.Body).Decode(&share)
+	if rec.Code != http.StatusOK || share.Link != "https://crossclues.example/?room=LUNCH" {
+		t.Fatalf("Expected a summary linking to the public URL, got %d %+v", rec.Code, share)
+	}
+
+	rec = serve(mux, http.MethodGet, "/api/rooms/LUNCH/state?playerName=Bob", "")
+	var state GameStateResponse
+	json.NewDecoder(rec.Body).Decode(&state)
+	if state.Share != share.Text {
+		t.Errorf("Expected the final state to carry the summary, got %q", state.Share)
+	}
+
+	// Only seated players may read it, with the password for a private room
+	CreateRoomWithOptions("SUPPER", "Alice", RoomOptions{GridSize: 3, Password: "sesame"})
+	for path, want := range map[string]int{
+		"/api/v1/rooms/LUNCH/share":                                  http.StatusBadRequest,
+		"/api/v1/rooms/LUNCH/share?playerName=Mallory":               http.StatusNotFound,
+		"/api/v1/rooms/SUPPER/share?playerName=Alice":                http.StatusForbidden,
+		"/api/v1/rooms/SUPPER/share?playerName=Alice&password=wrong": http.StatusForbidden,
+	} {
+		if rec := serve(mux, http.MethodGet, path, ""); rec.Code != want {
+			t.Errorf("%s: expected %d, got %d", path, want, rec.Code)
+		}
+	}
+	rec = serve(mux, http.MethodGet, "/api/v1/rooms/SUPPER/share?playerName=Alice&password=sesame", "")
+	if !strings.Contains(rec.Body.String(), CodeNoFinishedGame) {
+		t.Errorf("Expected the right password to get as far as %s, got %d: %s", CodeNoFinishedGame, rec.Code, rec.Body)
+	}
+}
@@ -0,0 +1,289 @@
+package main
+
+import (
+	"errors"
+	"fmt"
+	"math/rand"
+	"testing"
+	"time"
+)
+
+// checkInvariants reports the first broken invariant of a room. Callers
+// must hold room.mu.
+func checkInvariants(room *Room) error {
+	if room.GameOver && !room.GameStarted {
+		return errors.New("game is over but was never started")
+	}
+	if len(room.PlayerHands) != len(room.Players) {
+		return fmt.Errorf("%d hands for %d players", len(room.PlayerHands), len(room.Players))
+	}
+
+	// Every cell is in exactly one of deck, hand or resolved
+	where := make(map[Card]string)
+	place := func(card Card, at string) error {
+		if card.Row < 0 || card.Row >= room.GridSize || card.Column < 0 || card.Column >= room.GridSize {
+			return fmt.Errorf("card %v in %s is off the grid", card, at)
+		}
+		if prev, ok := where[card]; ok {
+			return fmt.Errorf("card %v is in both %s and %s", card, prev, at)
+		}
+		where[card] = at
+		return nil
+	}
+	for _, card := range room.CardDeck {
+		if err := place(card, "deck"); err != nil {
+			return err
+		}
+	}
+	limit := room.GetCardsPerPlayer()
+	held := 0
+	for _, player := range room.Players {
+		hand, ok := room.PlayerHands[player]
+		if !ok {
+			return fmt.Errorf("player %s has no hand", player)
+		}
+		// Hands over the current limit were dealt before more players
+		// joined, when the limit was at most 2
+		if len(hand) > 2 {
+			return fmt.Errorf("player %s holds %d cards", player, len(hand))
+		}
+		// A player short of cards while the deck has some can get stuck
+		if len(hand) < limit && len(room.CardDeck) > 0 && !room.GameOver {
+			return fmt.Errorf("player %s holds %d cards with %d in the deck", player, len(hand), len(room.CardDeck))
+		}
+		held += len(hand)
+		for _, card := range hand {
+			if err := place(card, "hand of "+player); err != nil {
+				return err
+			}
+		}
+	}
+	resolved := 0
+	for row := 0; row < room.GridSize; row++ {
+		for col := 0; col < room.GridSize; col++ {
+			cell := room.Grid[row][col]
+			if cell.GuessedCorrectly && cell.DiscardedBy != "" {
+				return fmt.Errorf("cell %d,%d is both guessed and discarded", row, col)
+			}
+			if !cell.GuessedCorrectly && cell.DiscardedBy == "" {
+				continue
+			}
+			resolved++
+			if err := place(Card{Row: row, Column: col}, "resolved"); err != nil {
+				return err
+			}
+		}
+	}
+	if len(where) != room.GridSize*room.GridSize {
+		return fmt.Errorf("%d of %d cells accounted for", len(where), room.GridSize*room.GridSize)
+	}
+
+	if room.GameStarted && !room.GameOver {
+		if resolved == room.GridSize*room.GridSize {
+			return errors.New("every cell is resolved but the game is not over")
+		}
+		if held == 0 && len(room.Players) > 0 {
+			return errors.New("nobody holds a card, so the game cannot finish")
+		}
+	}
+	return nil
+}
+
+// simulator plays one random game in one room
+type simulator struct {
+	rng       *rand.Rand
+	roomCode  string
+	nextID    int
+	log       []string       // operations so far, for failure reports
+	handSizes map[string]int // after the last step
+}
+
+func (s *simulator) newPlayer() string {
+	s.nextID++
+	return fmt.Sprintf("P%d", s.nextID)
+}
+
+func (s *simulator) room() *Room {
+	room, _ := getRoom(s.roomCode)
+	return room
+}
+
+// step records an operation, checks that it failed only in an allowed way
+// and checks the room's invariants afterwards
+func (s *simulator) step(op string, err error, allowed ...error) error {
+	s.log = append(s.log, fmt.Sprintf("%s: %v", op, err))
+	if err != nil {
+		ok := false
+		for _, a := range allowed {
+			ok = ok || errors.Is(err, a)
+		}
+		if !ok {
+			return fmt.Errorf("%s failed: %w", op, err)
+		}
+	}
+	room := s.room()
+	room.mu.RLock()
+	defer room.mu.RUnlock()
+	if err := checkInvariants(room); err != nil {
+		return fmt.Errorf("after %s: %w", op, err)
+	}
+
+	// Cards over the limit are kept until played, but never added to
+	limit := room.GetCardsPerPlayer()
+	sizes := make(map[string]int)
+	for _, player := range room.Players {
+		n := len(room.PlayerHands[player])
+		if prev, ok := s.handSizes[player]; n > limit && (!ok || n > prev) {
+			return fmt.Errorf("after %s: player %s went to %d cards, over the limit of %d", op, player, n, limit)
+		}
+		sizes[player] = n
+	}
+	s.handSizes = sizes
+	return nil
+}
+
+// snapshot returns the players and their hands
+func (s *simulator) snapshot() (players []string, hands map[string][]Card, over bool) {
+	room := s.room()
+	room.mu.RLock()
+	defer room.mu.RUnlock()
+	hands = make(map[string][]Card)
+	for _, p := range room.Players {
+		hands[p] = append([]Card(nil), room.PlayerHands[p]...)
+	}
+	return append([]string(nil), room.Players...), hands, room.GameOver
+}
+
+func (s *simulator) join() (cardsDealt int, err error) {
+	name := s.newPlayer()
+	cardsDealt, err = JoinRoom(s.roomCode, name)
+	return cardsDealt, s.step("join "+name, err, ErrRoomFull)
+}
+
+func (s *simulator) leave(players []string) error {
+	if len(players) == 0 {
+		return nil
+	}
+	name := players[s.rng.Intn(len(players))]
+	return s.step("leave "+name, LeaveRoom(s.roomCode, name))
+}
+
+func (s *simulator) guess(players []string, hands map[string][]Card) error {
+	var holders []string
+	for _, p := range players {
+		if len(hands[p]) > 0 {
+			holders = append(holders, p)
+		}
+	}
+	if len(holders) == 0 {
+		return nil
+	}
+	name := holders[s.rng.Intn(len(holders))]
+	card := hands[name][s.rng.Intn(len(hands[name]))]
+	correct := s.rng.Intn(3) > 0
+	_, err := SubmitGuess(s.roomCode, name, card.Row, card.Column, correct)
+	return s.step(fmt.Sprintf("guess %s %v %v", name, card, correct), err)
+}
+
+// play runs one game from creation until it is over, with joins, leaves
+// and restarts along the way
+func (s *simulator) play() error {
+	gridSize := config.MinGridSize + s.rng.Intn(config.MaxGridSize-config.MinGridSize+1)
+	creator := s.newPlayer()
+	_, err := CreateRoom(s.roomCode, gridSize, creator)
+	s.log = append(s.log, fmt.Sprintf("create %s %d %s: %v", s.roomCode, gridSize, creator, err))
+	if err != nil {
+		return err
+	}
+	defer DeleteRoom(s.roomCode)
+
+	for n := s.rng.Intn(6); n > 0; n-- {
+		if _, err := s.join(); err != nil {
+			return err
+		}
+	}
+	if err := s.step("start", StartGame(s.roomCode), ErrNotEnoughPlayers); err != nil {
+		return err
+	}
+
+	// Each guess resolves a cell, so a game that keeps being played ends
+	// within a bounded number of steps unless it is restarted
+	restarts := 0
+	maxSteps := 50 * gridSize * gridSize
+	for steps := 0; ; steps++ {
+		if steps > maxSteps {
+			return fmt.Errorf("game did not finish in %d steps", maxSteps)
+		}
+		players, hands, over := s.snapshot()
+		if over {
+			break
+		}
+
+		var err error
+		switch r := s.rng.Intn(100); {
+		case r < 6 || len(players) < 2:
+			_, err = s.join()
+		case r < 12:
+			err = s.leave(players)
+		case r < 13 && restarts < 3:
+			restarts++
+			err = s.step("restart", StartGame(s.roomCode), ErrNotEnoughPlayers)
+		case r < 14:
+			// Ends the game early, as the admin API does
+			err = s.step("end", EndGame(s.roomCode), ErrGameNotStarted)
+		default:
+			started := s.room().GameStarted
+			if !started {
+				err = s.step("start", StartGame(s.roomCode), ErrNotEnoughPlayers)
+			} else {
+				err = s.guess(players, hands)
+			}
+		}
+		if err != nil {
+			return err
+		}
+	}
+
+	// A finished game stays finished until it is restarted, so a player
+	// joining now is dealt nothing
+	cardsDealt, err := s.join()
+	if err != nil {
+		return err
+	}
+	if cardsDealt > 0 {
+		return fmt.Errorf("join after the game ended was dealt %d cards", cardsDealt)
+	}
+	players, _, _ := s.snapshot()
+	if err := s.leave(players); err != nil {
+		return err
+	}
+	return s.step("restart", StartGame(s.roomCode), ErrNotEnoughPlayers)
+}
+
+func TestSimulateGames(t *testing.T) {
+	ClearRooms()
+	games := 2000
+	if testing.Short() {
+		games = 200
+	}
+	seed := time.Now().UnixNano()
+	rng := rand.New(rand.NewSource(seed))
+
+	for i := 0; i < games; i++ {
+		s := &simulator{rng: rng, roomCode: fmt.Sprintf("SIM%d", i)}
+		if err := s.play(); err != nil {
+			t.Fatalf("Game %d (seed %d): %v\n%s", i, seed, err, joinLines(s.log))
+		}
+	}
+}
+
+func joinLines(lines []string) string {
+	if len(lines) > 40 {
+		lines = append([]string{"..."}, lines[len(lines)-40:]...)
+	}
+	out := ""
+	for _, l := range lines {
+		out += "  " + l + "\n"
+	}
+	return out
+}
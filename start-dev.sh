@@ -21,7 +21,7 @@ trap cleanup EXIT INT TERM
 printf "\n➡️  Starting Go backend on http://localhost:8080 ...\n"
 (
   cd "${ROOT_DIR}"
-  go run .
+  go run . -log-format text
 ) &
 BACKEND_PID=$!
 
@@ -0,0 +1,48 @@
+package main
+
+import "sync"
+
+// Room change notifications let long-lived connections push updates to
+// players instead of polling
+
+var (
+	watchersMu sync.Mutex
+	watchers   = make(map[string]map[chan struct{}]bool)
+)
+
+// WatchRoom returns a channel that receives a value after the room changes,
+// and a function to stop watching. Changes that arrive while a notification
+// is still pending are coalesced into it.
+func WatchRoom(roomCode string) (<-chan struct{}, func()) {
+	roomCode = normalizeRoomCode(roomCode)
+	ch := make(chan struct{}, 1)
+
+	watchersMu.Lock()
+	if watchers[roomCode] == nil {
+		watchers[roomCode] = make(map[chan struct{}]bool)
+	}
+	watchers[roomCode][ch] = true
+	watchersMu.Unlock()
+
+	stop := func() {
+		watchersMu.Lock()
+		defer watchersMu.Unlock()
+		delete(watchers[roomCode], ch)
+		if len(watchers[roomCode]) == 0 {
+			delete(watchers, roomCode)
+		}
+	}
+	return ch, stop
+}
+
+// notifyRoom wakes every watcher of a room without blocking
+func notifyRoom(roomCode string) {
+	watchersMu.Lock()
+	defer watchersMu.Unlock()
+	for ch := range watchers[roomCode] {
+		select {
+		case ch <- struct{}{}:
+		default:
+		}
+	}
+}
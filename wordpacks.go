@@ -0,0 +1,76 @@
+package main
+
+import (
+	"bufio"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+)
+
+// DefaultWordPack is the name of the built-in word list
+const DefaultWordPack = "default"
+
+// wordPacks maps pack names to their words. It is populated at startup and
+// read-only afterwards.
+var wordPacks = map[string][]string{
+	DefaultWordPack: wordList,
+}
+
+// loadWordPacks adds every *.txt file in dir as a word pack named after the
+// file. Files hold one word per line; blank lines and lines starting with #
+// are ignored. Each pack must have at least minWords distinct words.
+func loadWordPacks(dir string, minWords int) error {
+	paths, err := filepath.Glob(filepath.Join(dir, "*.txt"))
+	if err != nil {
+		return err
+	}
+
+	for _, path := range paths {
+		name := strings.TrimSuffix(filepath.Base(path), ".txt")
+		words, err := readWordPack(path)
+		if err != nil {
+			return err
+		}
+		if len(words) < minWords {
+			return fmt.Errorf("word pack %s has %d words, need at least %d", name, len(words), minWords)
+		}
+		wordPacks[name] = words
+	}
+	return nil
+}
+
+func readWordPack(path string) ([]string, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+
+	var words []string
+	seen := make(map[string]bool)
+	scanner := bufio.NewScanner(f)
+	for scanner.Scan() {
+		word := strings.ToUpper(strings.TrimSpace(scanner.Text()))
+		if word == "" || strings.HasPrefix(word, "#") || seen[word] {
+			continue
+		}
+		seen[word] = true
+		words = append(words, word)
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, fmt.Errorf("reading word pack %s: %w", path, err)
+	}
+	return words, nil
+}
+
+// WordPackNames returns the names of all loaded word packs, sorted
+func WordPackNames() []string {
+	names := make([]string, 0, len(wordPacks))
+	for name := range wordPacks {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}